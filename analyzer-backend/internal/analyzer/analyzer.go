@@ -1,15 +1,19 @@
 package analyzer
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
+	"time"
 
+	"ea-scanner/internal/cache"
 	"ea-scanner/internal/models"
-
-	"google.golang.org/genai"
+	"ea-scanner/internal/redact"
+	"ea-scanner/internal/rules"
 )
 
 const systemPrompt = `You are an expert employment law analyst specializing in detecting fraudulent job offers and risky employment contract clauses. Analyze the provided employment agreement/job offer and identify:
@@ -34,6 +38,14 @@ const systemPrompt = `You are an expert employment law analyst specializing in d
 - Unlimited liability or one-sided indemnification clauses
 - Excessive confidentiality restrictions
 
+## CONTRACT TERMS (extract as stated; omit any term the document does not state):
+- Employer name, role, start date (YYYY-MM-DD when a full date is given)
+- Base salary as amount, ISO 4217 currency and pay period
+- Bonus or commission terms
+- Notice period the employee must give, and probation length
+- Non-compete duration and geography
+- Work location, weekly hours and governing law
+
 ## MISSING ELEMENTS (red flags by absence):
 - No clearly defined compensation structure
 - Missing termination conditions
@@ -53,7 +65,16 @@ Respond ONLY with valid JSON in this exact format:
     {"category": "<type>", "severity": "<LOW|MEDIUM|HIGH|CRITICAL>", "description": "<explanation>", "quote": "<exact text from document>"}
   ],
   "missing_elements": ["<element1>", "<element2>"],
-  "recommendations": ["<action1>", "<action2>"]
+  "recommendations": ["<action1>", "<action2>"],
+  "contract_terms": {
+    "employer_name": "<name>", "role": "<title>", "start_date": "<YYYY-MM-DD>",
+    "base_salary": {"amount": <number>, "currency": "<ISO 4217>", "period": "<HOUR|DAY|WEEK|MONTH|YEAR>"},
+    "bonus": "<terms>",
+    "notice_period": {"value": <number>, "unit": "<DAY|WEEK|MONTH|YEAR>"},
+    "probation_period": {"value": <number>, "unit": "<DAY|WEEK|MONTH|YEAR>"},
+    "non_compete": {"duration": {"value": <number>, "unit": "<DAY|WEEK|MONTH|YEAR>"}, "geography": "<area>"},
+    "work_location": "<location>", "hours_per_week": <number>, "governing_law": "<jurisdiction>"
+  }
 }
 
 Risk Score Guidelines:
@@ -62,133 +83,262 @@ Risk Score Guidelines:
 - 51-75: HIGH - Multiple red flags, legal review strongly advised
 - 76-100: CRITICAL - Likely scam or extremely predatory terms`
 
-// Analyzer handles Gemini API interactions
-type Analyzer struct{}
+// redactionNote tells the model how to treat redacted text
+const redactionNote = `Personal data in the document has been replaced with placeholders such as [NAME_1], [PHONE_1] or [ACCOUNT_1]. Copy placeholders exactly as written when quoting, and do not treat redaction itself as a red flag.`
+
+// analysisSchema constrains and validates agreement analysis output
+var analysisSchema = SchemaFor(models.AnalysisResult{})
+
+// DefaultModel is the Gemini model used for agreement analysis when no model
+// is requested
+const DefaultModel = "gemini-2.0-flash"
 
-// New creates a new Analyzer
-func New() *Analyzer {
-	return &Analyzer{}
+// Analyzer analyzes employment agreements using an LLM provider
+type Analyzer struct {
+	provider Provider
+	config   Config
+	scanner  *rules.Scanner
 }
 
-// Analyze processes the document text using the client's Gemini API key
-func (a *Analyzer) Analyze(ctx context.Context, apiKey, documentText string) (*models.AnalysisResult, error) {
-	// Create client with user's API key
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  apiKey,
-		Backend: genai.BackendGeminiAPI,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+// New creates a new Analyzer backed by the given provider
+func New(provider Provider, config Config) *Analyzer {
+	return &Analyzer{
+		provider: provider,
+		config:   config,
+		scanner:  rules.NewScanner(rules.Builtin()),
 	}
+}
+
+// Options are per-request analysis options
+type Options struct {
+	Model          string                    // Model to analyze with; empty selects the configured default
+	Jurisdiction   string                    // Jurisdiction code selecting an extra rule pack, e.g. "IN"; empty for none
+	SkipRedaction  bool                      // Send the text to the provider without redacting personal data
+	NoCache        bool                      // Ignore cached results; the fresh result still replaces the cached one
+	Email          *models.EmailHeaders      // Headers of an uploaded email, checked for sender red flags
+	Clauses        []*models.Clause          // Clause tree of the document, for chunking and citing clauses
+	TrackedChanges []models.TrackedChange    // Unresolved tracked changes, which the model never sees
+	Metadata       []models.DocumentMetadata // Properties of the document and its attachments, checked for forensic red flags
+	Contacts       []models.Contact          // URLs, email addresses, phone numbers and domains, checked for reputation red flags
+}
 
-	// Prepare the combined prompt
-	fullPrompt := fmt.Sprintf("%s\n\nAnalyze this employment agreement/job offer:\n\n---\n%s\n---", systemPrompt, documentText)
+// Analyze processes the document text using the built-in rules and the
+// configured provider. If the model call fails but rules matched, a partial
+// rule-based result is returned instead of an error.
+func (a *Analyzer) Analyze(ctx context.Context, apiKey, documentText string, opts Options) (*models.AnalysisResult, error) {
+	start := time.Now()
 
-	// Generate analysis using the simpler text-only approach
-	resp, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash",
-		genai.Text(fullPrompt),
-		nil,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate analysis: %w", err)
+	var pack *rules.Pack
+	if opts.Jurisdiction != "" {
+		var err error
+		if pack, err = rules.Jurisdiction(opts.Jurisdiction); err != nil {
+			return nil, err
+		}
 	}
 
-	// Extract text response
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("empty response from Gemini")
+	if err := a.config.CheckModel(opts.Model); err != nil {
+		return nil, err
 	}
+	model := cmp.Or(opts.Model, a.config.Model)
 
-	responseText := resp.Text()
+	redacting := a.config.Redact && !opts.SkipRedaction
+	instructions := instructionsFor(pack)
+	if note := metadataNote(opts.Metadata, redacting); note != "" {
+		instructions += "\n\n" + note
+	}
 
-	// Parse JSON response
-	result, err := parseAnalysisResponse(responseText)
+	// Serve repeated documents from the cache. The key covers the full prompt,
+	// so editing the prompt or a rule pack invalidates old entries, and the
+	// caller's API key, so results are never shared between callers.
+	var cacheKey, cacheStatus string
+	if a.config.Cache != nil {
+		cacheKey = cache.Key("analysis", a.provider.Name(), apiKey, model, instructions,
+			fmt.Sprint(redacting, a.config.DropUnverified, a.config.ChunkSize, a.config.ChunkOverlap), dataKey(opts.Email), dataKey(opts.TrackedChanges), dataKey(opts.Metadata), dataKey(opts.Contacts), documentText)
+		cacheStatus = cache.StatusBypass
+		if !opts.NoCache {
+			if cached, ok := cacheLookup[models.AnalysisResult](ctx, a.config, cacheKey); ok {
+				cached.CacheStatus = cache.StatusHit
+				cached.Usage = cachedUsage(cached.Usage, start)
+				citeClauses(cached.ScamIndicators, opts.Clauses)
+				citeClauses(cached.RiskyClauses, opts.Clauses)
+				return cached, nil
+			}
+			cacheStatus = cache.StatusMiss
+		}
+	}
+
+	// Run deterministic rules first; they work even when the model does not
+	hits := a.scanner.Scan(documentText)
+	if pack != nil {
+		hits = append(hits, rules.NewScanner(pack).Scan(documentText)...)
+	}
+	ruleIndicators := findingsFromHits(hitsOfKind(hits, rules.KindScam), documentText)
+	ruleClauses := findingsFromHits(hitsOfKind(hits, rules.KindClause), documentText)
+	ruleCount := len(ruleIndicators) + len(ruleClauses)
+	emit(ctx, Event{Stage: StageRules, Message: fmt.Sprintf("%d rule hit(s)", ruleCount), Data: append(slices.Clone(ruleIndicators), ruleClauses...)})
+
+	// Replace personal data with placeholders before the text leaves the server
+	var red *redact.Result
+	if redacting {
+		red = redact.Redact(documentText)
+		log.Printf("Redacted %d personal data value(s) before analysis", len(red.Entities))
+	}
+
+	result, err := a.analyzeWithModel(ctx, apiKey, model, documentText, instructions, red, opts.Clauses)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse analysis response: %w", err)
+		if ruleCount == 0 || ctx.Err() != nil {
+			return nil, err
+		}
+		log.Printf("Model analysis failed, returning rule-based result: %v", err)
+		result = ruleOnlyResult(ruleCount, err)
 	}
+	result.Redaction = redactionReport(red)
+	citeRules(result.ScamIndicators, pack)
+	citeRules(result.RiskyClauses, pack)
+
+	// Flag anomalies in the extracted terms; jurisdiction limits take precedence
+	result.RiskyClauses = append(result.RiskyClauses, checkJurisdictionTerms(result.Terms, pack, result.ScamIndicators, result.RiskyClauses)...)
+	result.RiskyClauses = append(result.RiskyClauses, checkTerms(result.Terms, result.ScamIndicators, result.RiskyClauses)...)
+	result.RiskyClauses = append(result.RiskyClauses, checkTrackedChanges(opts.TrackedChanges, result.ScamIndicators, result.RiskyClauses)...)
+
+	// Flag sender red flags in email headers, which the model never sees,
+	// forensic red flags in document metadata and suspicious contacts
+	emailIndicators := checkEmailHeaders(opts.Email, result.ScamIndicators)
+	result.ScamIndicators = append(result.ScamIndicators, emailIndicators...)
+	metadataIndicators := checkMetadata(opts.Metadata, opts.Email, result.ScamIndicators)
+	result.ScamIndicators = append(result.ScamIndicators, metadataIndicators...)
+	var contactIndicators []models.Finding
+	result.Contacts, contactIndicators = checkContacts(opts.Contacts, opts.Email, documentText, result.ScamIndicators)
+	result.ScamIndicators = append(result.ScamIndicators, contactIndicators...)
+
+	// Merge rule hits; they and the header, metadata and contact checks set a
+	// floor on the risk score
+	result.ScamIndicators = mergeRuleFindings(result.ScamIndicators, ruleIndicators)
+	result.RiskyClauses = mergeRuleFindings(result.RiskyClauses, ruleClauses)
+	if ruleScore := scoreFindings(ruleIndicators, emailIndicators, metadataIndicators, contactIndicators, ruleClauses); ruleScore > result.RiskScore {
+		result.RiskScore = ruleScore
+		result.RiskLevel = riskLevelForScore(ruleScore)
+	}
+	citeClauses(result.ScamIndicators, opts.Clauses)
+	citeClauses(result.RiskyClauses, opts.Clauses)
+
+	if result.Usage == nil {
+		result.Usage = &models.Usage{Model: model}
+	}
+	result.Usage.LatencyMS = time.Since(start).Milliseconds()
+
+	// Partial results are not cached so the next request retries the model
+	stored := false
+	if cacheKey != "" && !result.Partial {
+		stored = cacheStore(ctx, a.config, cacheKey, result)
+	}
+	result.CacheStatus = storedStatus(cacheStatus, stored)
 
 	return result, nil
 }
 
-// parseAnalysisResponse extracts JSON from the Gemini response
-func parseAnalysisResponse(response string) (*models.AnalysisResult, error) {
-	// Clean up response - find JSON content
-	response = strings.TrimSpace(response)
-
-	// Remove markdown code block if present
-	if strings.Contains(response, "```json") {
-		start := strings.Index(response, "```json")
-		end := strings.LastIndex(response, "```")
-		if start != -1 && end > start+7 {
-			response = strings.TrimSpace(response[start+7 : end])
-		}
-	} else if strings.Contains(response, "```") {
-		// Generic code block
-		start := strings.Index(response, "```")
-		end := strings.LastIndex(response, "```")
-		if end > start+3 {
-			response = strings.TrimSpace(response[start+3 : end])
-		}
+// instructionsFor returns the analysis instructions, extended with the
+// jurisdiction pack's prompt addendum if there is one
+func instructionsFor(pack *rules.Pack) string {
+	if pack == nil || pack.Prompt == "" {
+		return systemPrompt
 	}
+	return fmt.Sprintf("%s\n\n## JURISDICTION: %s (%s)\n%s", systemPrompt, pack.Name, pack.Jurisdiction, pack.Prompt)
+}
+
+// analyzeWithModel runs the LLM analysis, splitting long documents into
+// chunks at clause boundaries, and verifies the resulting quotes against the
+// full text. If red is set, the model sees the redacted text and placeholders
+// in its output are restored before verification.
+func (a *Analyzer) analyzeWithModel(ctx context.Context, apiKey, model, documentText, instructions string, red *redact.Result, clauses []*models.Clause) (*models.AnalysisResult, error) {
+	var result *models.AnalysisResult
+	var err error
 
-	// Find JSON object with proper brace matching
-	start := strings.Index(response, "{")
-	if start == -1 {
-		return nil, fmt.Errorf("no JSON object found in response")
+	modelText := documentText
+	if red != nil {
+		modelText = red.Text
+		instructions += "\n\n" + redactionNote
 	}
 
-	// Find matching closing brace
-	braceCount := 0
-	end := -1
-	for i := start; i < len(response); i++ {
-		if response[i] == '{' {
-			braceCount++
-		} else if response[i] == '}' {
-			braceCount--
-			if braceCount == 0 {
-				end = i
-				break
-			}
+	chunks := splitChunks(modelText, a.config.ChunkSize, a.config.ChunkOverlap, clauseBoundaries(clauses, documentText, modelText))
+	emit(ctx, Event{Stage: StageLLMStarted, Message: fmt.Sprintf("Analyzing %d part(s) with %s", len(chunks), model), Data: map[string]int{"chunks": len(chunks)}})
+
+	if len(chunks) == 1 {
+		fullPrompt := fmt.Sprintf("%s\n\nAnalyze this employment agreement/job offer:\n\n---\n%s\n---", instructions, modelText)
+		result, err = a.generate(ctx, apiKey, model, fullPrompt)
+		if err == nil {
+			result.ChunkCount = 1
+			a.emitPartial(ctx, 1, 1, result, documentText, red)
 		}
+	} else {
+		log.Printf("Document split into %d chunks", len(chunks))
+		result, err = a.analyzeChunks(ctx, apiKey, model, documentText, instructions, chunks, red)
+	}
+	if err != nil {
+		return nil, err
+	}
+	restoreResult(result, red)
+
+	// Check every quote against the document
+	result.ScamIndicators = verifyFindings(result.ScamIndicators, documentText, a.config.DropUnverified)
+	result.RiskyClauses = verifyFindings(result.RiskyClauses, documentText, a.config.DropUnverified)
+	markSource(result.ScamIndicators, SourceLLM)
+	markSource(result.RiskyClauses, SourceLLM)
+
+	return result, nil
+}
+
+// generate sends one analysis prompt, repairing invalid output as needed
+func (a *Analyzer) generate(ctx context.Context, apiKey, model, prompt string) (*models.AnalysisResult, error) {
+	result, gen, err := generateStructured(ctx, a.provider, a.config.Retry, &GenerateRequest{
+		APIKey: apiKey,
+		Model:  model,
+		Prompt: prompt,
+		Schema: analysisSchema,
+	}, parseAnalysisResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate analysis after %d attempt(s): %w", gen.attempts, err)
 	}
+	result.Attempts = gen.attempts
+	result.Usage = a.config.usageFor(gen)
+	return result, nil
+}
 
-	if end == -1 {
-		return nil, fmt.Errorf("no matching closing brace found")
+// ruleOnlyResult builds a partial result from rule findings when the model
+// analysis is unavailable
+func ruleOnlyResult(ruleCount int, modelErr error) *models.AnalysisResult {
+	return &models.AnalysisResult{
+		ScamIndicators:  []models.Finding{},
+		RiskyClauses:    []models.Finding{},
+		MissingElements: []string{},
+		Recommendations: []string{
+			"Automated analysis was incomplete; have the full agreement reviewed before signing",
+			"Do not send money or financial details to a prospective employer",
+		},
+		Summary:  fmt.Sprintf("Detailed AI analysis was unavailable, but %d issue(s) were detected by built-in rules. Treat this offer with caution.", ruleCount),
+		Partial:  true,
+		Warnings: []string{"Model analysis failed: " + modelErr.Error()},
 	}
+}
 
-	jsonStr := response[start : end+1]
+// parseAnalysisResponse validates the model output against the analysis schema
+// and decodes it
+func parseAnalysisResponse(response string) (*models.AnalysisResult, error) {
+	data := []byte(strings.TrimSpace(response))
 
-	var result models.AnalysisResult
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+	if err := analysisSchema.Validate(data); err != nil {
 		// Log the problematic JSON for debugging (truncate if too long)
-		logLen := len(jsonStr)
+		logLen := len(data)
 		if logLen > 500 {
 			logLen = 500
 		}
-		log.Printf("Failed to parse JSON: %s", jsonStr[:logLen])
-		return nil, fmt.Errorf("invalid JSON format: %w", err)
+		log.Printf("Analysis response failed validation: %s", data[:logLen])
+		return nil, err
 	}
 
-	// Validate risk score
-	if result.RiskScore < 0 {
-		result.RiskScore = 0
-	} else if result.RiskScore > 100 {
-		result.RiskScore = 100
-	}
-
-	// Validate risk level
-	validLevels := map[string]bool{"LOW": true, "MEDIUM": true, "HIGH": true, "CRITICAL": true}
-	if !validLevels[result.RiskLevel] {
-		// Derive from score
-		switch {
-		case result.RiskScore <= 25:
-			result.RiskLevel = "LOW"
-		case result.RiskScore <= 50:
-			result.RiskLevel = "MEDIUM"
-		case result.RiskScore <= 75:
-			result.RiskLevel = "HIGH"
-		default:
-			result.RiskLevel = "CRITICAL"
-		}
+	var result models.AnalysisResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid JSON format: %w", err)
 	}
 
 	return &result, nil
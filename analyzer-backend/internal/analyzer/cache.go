@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"ea-scanner/internal/cache"
+	"ea-scanner/internal/models"
+)
+
+// dataKey identifies request data that is not part of the document text,
+// such as email headers, in cache keys
+func dataKey(value any) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// cacheLookup returns the value cached under key, if any. Cache errors are
+// logged and treated as misses.
+func cacheLookup[T any](ctx context.Context, config Config, key string) (*T, bool) {
+	data, err := config.Cache.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, cache.ErrMiss) {
+			log.Printf("Cache read failed: %v", err)
+		}
+		return nil, false
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		log.Printf("Discarding unreadable cache entry: %v", err)
+		return nil, false
+	}
+	return &value, true
+}
+
+// cacheStore caches value under key and reports whether it was stored,
+// logging failures
+func cacheStore(ctx context.Context, config Config, key string, value any) bool {
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = config.Cache.Set(ctx, key, data, config.CacheTTL)
+	}
+	if err != nil {
+		log.Printf("Cache write failed: %v", err)
+		return false
+	}
+	return true
+}
+
+// storedStatus returns the cache status of a computed result: a miss that
+// was not stored is reported as such
+func storedStatus(status string, stored bool) string {
+	if status == cache.StatusMiss && !stored {
+		return cache.StatusNotStored
+	}
+	return status
+}
+
+// cachedUsage reports the usage of a cache hit: no tokens and no cost
+func cachedUsage(stored *models.Usage, start time.Time) *models.Usage {
+	usage := &models.Usage{LatencyMS: time.Since(start).Milliseconds()}
+	if stored != nil {
+		usage.Model = stored.Model
+	}
+	return usage
+}
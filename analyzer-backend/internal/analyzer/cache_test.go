@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ea-scanner/internal/cache"
+)
+
+const validAnalysis = `{"risk_score":10,"risk_level":"LOW","scam_indicators":[],"risky_clauses":[],"missing_elements":[],"recommendations":[],"summary":"Looks fine."}`
+
+func TestAnalyzeCache(t *testing.T) {
+	type step struct {
+		apiKey     string
+		noCache    bool
+		wait       time.Duration
+		wantStatus string
+		wantCalls  int // Provider calls made so far
+	}
+
+	tests := []struct {
+		name  string
+		ttl   time.Duration
+		steps []step
+	}{
+		{
+			name: "repeated document",
+			ttl:  time.Hour,
+			steps: []step{
+				{apiKey: "key-a", wantStatus: cache.StatusMiss, wantCalls: 1},
+				{apiKey: "key-a", wantStatus: cache.StatusHit, wantCalls: 1},
+			},
+		},
+		{
+			name: "scoped to the caller's API key",
+			ttl:  time.Hour,
+			steps: []step{
+				{apiKey: "key-a", wantStatus: cache.StatusMiss, wantCalls: 1},
+				{apiKey: "key-b", wantStatus: cache.StatusMiss, wantCalls: 2},
+				{apiKey: "key-a", wantStatus: cache.StatusHit, wantCalls: 2},
+			},
+		},
+		{
+			name: "bypass",
+			ttl:  time.Hour,
+			steps: []step{
+				{apiKey: "key-a", wantStatus: cache.StatusMiss, wantCalls: 1},
+				{apiKey: "key-a", noCache: true, wantStatus: cache.StatusBypass, wantCalls: 2},
+			},
+		},
+		{
+			name: "expired",
+			ttl:  10 * time.Millisecond,
+			steps: []step{
+				{apiKey: "key-a", wantStatus: cache.StatusMiss, wantCalls: 1},
+				{apiKey: "key-a", wait: 20 * time.Millisecond, wantStatus: cache.StatusMiss, wantCalls: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewFakeProvider(validAnalysis)
+			config := DefaultConfig()
+			config.Cache = cache.NewMemoryStore(10)
+			config.CacheTTL = tt.ttl
+			a := New(provider, config)
+
+			for i, s := range tt.steps {
+				time.Sleep(s.wait)
+				result, err := a.Analyze(context.Background(), s.apiKey, "The Employee shall be paid monthly.", Options{NoCache: s.noCache})
+				if err != nil {
+					t.Fatalf("step %d: Analyze() error = %v", i, err)
+				}
+				if result.CacheStatus != s.wantStatus || provider.Calls() != s.wantCalls {
+					t.Errorf("step %d: cache status %q after %d call(s), want %q after %d", i, result.CacheStatus, provider.Calls(), s.wantStatus, s.wantCalls)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"ea-scanner/internal/models"
+)
+
+// ErrModelNotAllowed is returned when a request names a model outside the allow-list
+var ErrModelNotAllowed = errors.New("model not allowed")
+
+// contextLimits are input token limits of known models, matched by name prefix
+var contextLimits = map[string]int{
+	"gemini-2.0-flash":      1048576,
+	"gemini-2.0-flash-lite": 1048576,
+	"gemini-2.5-flash":      1048576,
+	"gemini-2.5-flash-lite": 1048576,
+	"gemini-2.5-pro":        1048576,
+}
+
+// DefaultAllowedModels returns the Gemini models clients may request by default
+func DefaultAllowedModels() []string {
+	return []string{
+		"gemini-2.0-flash",
+		"gemini-2.0-flash-lite",
+		"gemini-2.5-flash",
+		"gemini-2.5-flash-lite",
+		"gemini-2.5-pro",
+	}
+}
+
+// allowedModels returns the allow-list; the default models are always allowed
+func (c Config) allowedModels() []string {
+	allowed := slices.Clone(c.AllowedModels)
+	for _, model := range []string{c.Model, c.ResumeModel} {
+		if model != "" && !slices.Contains(allowed, model) {
+			allowed = append(allowed, model)
+		}
+	}
+	return allowed
+}
+
+// CheckModel returns an error wrapping ErrModelNotAllowed unless the model is
+// allowed. An empty model selects the default and is always allowed.
+func (c Config) CheckModel(model string) error {
+	if model == "" {
+		return nil
+	}
+	allowed := c.allowedModels()
+	if !slices.Contains(allowed, model) {
+		return fmt.Errorf("%w: %q (allowed: %s)", ErrModelNotAllowed, model, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// Models describes the allowed models with their context limits and prices.
+// Relative cost compares a blended price of three input tokens per output
+// token against the cheapest priced model.
+func (c Config) Models() []models.ModelInfo {
+	allowed := c.allowedModels()
+	infos := make([]models.ModelInfo, 0, len(allowed))
+	cheapest := math.Inf(1)
+	for _, name := range allowed {
+		info := models.ModelInfo{Name: name}
+		if limit, ok := lookupModel(contextLimits, name); ok {
+			info.ContextTokens = limit
+		}
+		if price, ok := lookupModel(c.Prices, name); ok {
+			info.InputPrice, info.OutputPrice = price.Input, price.Output
+			if blended := blendedPrice(price); blended > 0 {
+				cheapest = min(cheapest, blended)
+			}
+		}
+		infos = append(infos, info)
+	}
+
+	for i := range infos {
+		blended := blendedPrice(Price{Input: infos[i].InputPrice, Output: infos[i].OutputPrice})
+		if blended > 0 {
+			infos[i].RelativeCost = math.Round(blended/cheapest*100) / 100
+		}
+	}
+	return infos
+}
+
+// blendedPrice weights input three to one against output, roughly the mix of
+// a document analysis call
+func blendedPrice(p Price) float64 {
+	return (3*p.Input + p.Output) / 4
+}
@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestConfigModels(t *testing.T) {
+	openai := DefaultConfig()
+	openai.Model, openai.ResumeModel = "llama3", "llama3"
+	openai.AllowedModels = []string{"qwen2.5"}
+
+	tests := []struct {
+		name       string
+		config     Config
+		want       []string
+		allowed    []string
+		notAllowed []string
+	}{
+		{
+			name:       "gemini defaults",
+			config:     DefaultConfig(),
+			want:       DefaultAllowedModels(),
+			allowed:    []string{"", DefaultModel, DefaultResumeModel},
+			notAllowed: []string{"llama3"},
+		},
+		{
+			name:       "openai-compatible",
+			config:     openai,
+			want:       []string{"qwen2.5", "llama3"},
+			allowed:    []string{"", "llama3", "qwen2.5"},
+			notAllowed: []string{DefaultModel, DefaultResumeModel},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, info := range tt.config.Models() {
+				names = append(names, info.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("Models() = %v, want %v", names, tt.want)
+			}
+			for _, model := range tt.allowed {
+				if err := tt.config.CheckModel(model); err != nil {
+					t.Errorf("CheckModel(%q) error = %v", model, err)
+				}
+			}
+			for _, model := range tt.notAllowed {
+				if err := tt.config.CheckModel(model); !errors.Is(err, ErrModelNotAllowed) {
+					t.Errorf("CheckModel(%q) error = %v, want ErrModelNotAllowed", model, err)
+				}
+			}
+		})
+	}
+}
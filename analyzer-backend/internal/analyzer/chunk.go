@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Boundary ranks: higher ranks are preferred split points
+const (
+	boundaryWord = iota
+	boundarySentence
+	boundaryParagraph
+	boundaryClause
+	boundarySection
+)
+
+// clauseStartPattern matches the start of a numbered clause or a section heading
+var clauseStartPattern = regexp.MustCompile(`(?:^|[\s])((?:\d+\.)+\d*\s|\d+\)\s|\([a-z]\)\s|\((?:i|ii|iii|iv|v|vi|vii|viii|ix|x)\)\s|(?i:article|section|clause|schedule|annexure|annex|appendix|exhibit)\s+[0-9A-Z])`)
+
+// sentenceEndPattern matches the end of a sentence followed by whitespace
+var sentenceEndPattern = regexp.MustCompile(`[.;:!?]["')\]]?\s`)
+
+// chunk is a contiguous slice of the document
+type chunk struct {
+	Text  string
+	Start int // Byte offset in the document
+}
+
+type boundary struct {
+	pos  int
+	rank int
+}
+
+// splitChunks splits text into chunks of at most size bytes, preferring
+// clause and paragraph boundaries. Clause starts come from structure when
+// given, and from clause numbering in the text otherwise. Consecutive chunks
+// share roughly overlap bytes so that clauses spanning a split are seen whole
+// at least once.
+func splitChunks(text string, size, overlap int, structure []boundary) []chunk {
+	if size <= 0 || len(text) <= size {
+		return []chunk{{Text: text}}
+	}
+	if overlap < 0 || overlap >= size/2 {
+		overlap = size / 4
+	}
+
+	boundaries := findBoundaries(text, structure)
+
+	var chunks []chunk
+	start := 0
+	for start < len(text) {
+		if len(text)-start <= size {
+			chunks = append(chunks, chunk{Text: text[start:], Start: start})
+			break
+		}
+
+		// Best split point in the second half of the window
+		end := bestBoundary(boundaries, start+size/2, start+size)
+		if end <= start {
+			// No boundary, as in text without spaces: split between runes
+			end = start + size
+			for end > start+1 && !utf8.RuneStart(text[end]) {
+				end--
+			}
+		}
+		chunks = append(chunks, chunk{Text: text[start:end], Start: start})
+
+		// Step back by the overlap, snapping to a boundary
+		next := nextBoundary(boundaries, end-overlap)
+		if next <= start || next > end {
+			next = end
+		}
+		start = next
+	}
+
+	return chunks
+}
+
+// findBoundaries returns all candidate split points in text, sorted by position
+func findBoundaries(text string, structure []boundary) []boundary {
+	ranks := map[int]int{}
+	add := func(pos, rank int) {
+		if pos > 0 && pos < len(text) && rank >= ranks[pos] {
+			ranks[pos] = rank
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\f':
+			add(i+1, boundaryClause)
+		case '\n':
+			add(i+1, boundaryParagraph)
+		case ' ', '\t':
+			add(i+1, boundaryWord)
+		}
+	}
+	for _, loc := range sentenceEndPattern.FindAllStringIndex(text, -1) {
+		add(loc[1], boundarySentence)
+	}
+	for _, b := range structure {
+		add(b.pos, b.rank)
+	}
+	if structure == nil {
+		for _, loc := range clauseStartPattern.FindAllStringSubmatchIndex(text, -1) {
+			add(loc[2], boundaryClause)
+		}
+	}
+
+	boundaries := make([]boundary, 0, len(ranks))
+	for pos, rank := range ranks {
+		boundaries = append(boundaries, boundary{pos: pos, rank: rank})
+	}
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].pos < boundaries[j].pos
+	})
+	return boundaries
+}
+
+// bestBoundary returns the highest-ranked boundary in (lo, hi], preferring
+// the latest one within a rank. It returns -1 if there is none.
+func bestBoundary(boundaries []boundary, lo, hi int) int {
+	best, bestRank := -1, -1
+	i := sort.Search(len(boundaries), func(i int) bool { return boundaries[i].pos > lo })
+	for ; i < len(boundaries) && boundaries[i].pos <= hi; i++ {
+		if boundaries[i].rank >= bestRank {
+			best, bestRank = boundaries[i].pos, boundaries[i].rank
+		}
+	}
+	return best
+}
+
+// nextBoundary returns the first sentence-or-better boundary at or after pos,
+// falling back to the first word boundary
+func nextBoundary(boundaries []boundary, pos int) int {
+	i := sort.Search(len(boundaries), func(i int) bool { return boundaries[i].pos >= pos })
+	word := -1
+	for j := i; j < len(boundaries); j++ {
+		if boundaries[j].rank >= boundarySentence {
+			return boundaries[j].pos
+		}
+		if word < 0 {
+			word = boundaries[j].pos
+		}
+		if boundaries[j].pos-pos > 2000 {
+			break
+		}
+	}
+	return word
+}
+
+// similarQuotes reports whether two quotes are near-identical
+func similarQuotes(a, b string) bool {
+	na, nb := normalizeForMatch(a), normalizeForMatch(b)
+	if na == "" || nb == "" {
+		return false
+	}
+	if strings.Contains(na, nb) || strings.Contains(nb, na) {
+		return true
+	}
+
+	wa, wb := strings.Fields(na), strings.Fields(nb)
+	longest := max(len(wa), len(wb))
+	return 1-float64(wordDistance(wa, wb))/float64(longest) >= 0.9
+}
@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitChunks(t *testing.T) {
+	clauses := strings.Repeat("The Employee shall keep records of all work performed. ", 3)
+	numbered := "1. Duties\n" + clauses + "\n2. Pay\n" + clauses + "\n3. Leave\n" + clauses
+
+	tests := []struct {
+		name       string
+		text       string
+		size       int
+		overlap    int
+		wantChunks int
+		wantStarts []string // Text each chunk must start with, if given
+	}{
+		{
+			name:       "fits in one chunk",
+			text:       "Short agreement.",
+			size:       100,
+			wantChunks: 1,
+		},
+		{
+			name:       "clause boundaries",
+			text:       numbered,
+			size:       200,
+			wantChunks: 3,
+			wantStarts: []string{"1. Duties", "2. Pay", "3. Leave"},
+		},
+		{
+			name:       "text without spaces",
+			text:       strings.Repeat("雇用契約書の条項。", 40),
+			size:       100,
+			overlap:    10,
+			wantChunks: -1,
+		},
+		{
+			name:       "multibyte words",
+			text:       strings.Repeat("Заработная плата выплачивается ежемесячно ", 20),
+			size:       101,
+			overlap:    20,
+			wantChunks: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := splitChunks(tt.text, tt.size, tt.overlap, nil)
+			if tt.wantChunks >= 0 && len(chunks) != tt.wantChunks {
+				t.Fatalf("splitChunks() = %d chunks, want %d", len(chunks), tt.wantChunks)
+			}
+
+			covered := 0
+			for i, c := range chunks {
+				if !utf8.ValidString(c.Text) {
+					t.Errorf("chunk %d is not valid UTF-8: %q", i, c.Text)
+				}
+				if len(chunks) > 1 && len(c.Text) > tt.size {
+					t.Errorf("chunk %d is %d bytes, want at most %d", i, len(c.Text), tt.size)
+				}
+				if tt.text[c.Start:c.Start+len(c.Text)] != c.Text {
+					t.Errorf("chunk %d does not match the text at offset %d", i, c.Start)
+				}
+				if c.Start > covered {
+					t.Errorf("chunk %d starts at %d, leaving a gap after %d", i, c.Start, covered)
+				}
+				covered = c.Start + len(c.Text)
+				if i < len(tt.wantStarts) && !strings.HasPrefix(c.Text, tt.wantStarts[i]) {
+					t.Errorf("chunk %d starts %q, want %q", i, c.Text[:min(len(c.Text), 20)], tt.wantStarts[i])
+				}
+			}
+			if covered != len(tt.text) {
+				t.Errorf("chunks end at %d, want %d", covered, len(tt.text))
+			}
+		})
+	}
+}
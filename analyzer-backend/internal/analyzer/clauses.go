@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"unicode/utf8"
+
+	"ea-scanner/internal/models"
+)
+
+// citeClauses sets the clause ID of findings whose quote was located in the
+// document to the innermost clause containing it
+func citeClauses(findings []models.Finding, clauses []*models.Clause) {
+	for i := range findings {
+		f := &findings[i]
+		if !f.Verified || f.Quote == "" {
+			continue
+		}
+		if c := clauseAt(clauses, f.StartOffset); c != nil {
+			f.ClauseID = c.ID
+		}
+	}
+}
+
+// clauseAt returns the innermost clause containing character offset pos
+func clauseAt(clauses []*models.Clause, pos int) *models.Clause {
+	for _, c := range clauses {
+		if pos >= c.StartOffset && pos < c.EndOffset {
+			if inner := clauseAt(c.Children, pos); inner != nil {
+				return inner
+			}
+			return c
+		}
+	}
+	return nil
+}
+
+// clauseBoundaries returns clause starts as split points in text, which is
+// the document text or its redacted copy. Top-level clauses rank above their
+// children. Clauses start at paragraphs, which redaction keeps, so they are
+// located by paragraph index; nil is returned if the paragraphs of the two
+// texts do not line up.
+func clauseBoundaries(clauses []*models.Clause, documentText, text string) []boundary {
+	if len(clauses) == 0 {
+		return nil
+	}
+	docStarts, starts := paragraphStarts(documentText), paragraphStarts(text)
+	if len(docStarts) != len(starts) {
+		return nil
+	}
+
+	// Paragraph index by character offset in the document text
+	index := make(map[int]int, len(docStarts))
+	chars, prev := 0, 0
+	for i, pos := range docStarts {
+		chars += utf8.RuneCountInString(documentText[prev:pos])
+		prev = pos
+		index[chars] = i
+	}
+
+	var boundaries []boundary
+	var walk func(clauses []*models.Clause, rank int)
+	walk = func(clauses []*models.Clause, rank int) {
+		for _, c := range clauses {
+			if i, ok := index[c.StartOffset]; ok {
+				boundaries = append(boundaries, boundary{pos: starts[i], rank: rank})
+			}
+			walk(c.Children, boundaryClause)
+		}
+	}
+	walk(clauses, boundarySection)
+	return boundaries
+}
+
+// paragraphStarts returns the byte offsets at which paragraphs of normalized
+// text start. Paragraphs hold no line breaks, so any line break ends one,
+// even where redaction has replaced part of a blank line.
+func paragraphStarts(text string) []int {
+	var starts []int
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' || text[i] == '\f' {
+			continue
+		}
+		if i == 0 || text[i-1] == '\n' || text[i-1] == '\f' {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
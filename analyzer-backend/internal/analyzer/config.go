@@ -0,0 +1,39 @@
+package analyzer
+
+import (
+	"time"
+
+	"ea-scanner/internal/cache"
+)
+
+// Config holds analyzer configuration
+type Config struct {
+	Model          string           // Agreement analysis model when the request names none
+	ResumeModel    string           // Resume analysis model when the request names none
+	Retry          RetryPolicy      // Repair and retry behaviour for model calls
+	DropUnverified bool             // Drop findings whose quote is not in the document instead of downgrading them
+	ChunkSize      int              // Documents longer than this (bytes) are analyzed in chunks
+	ChunkOverlap   int              // Bytes shared between consecutive chunks
+	Concurrency    int              // Maximum chunks analyzed at once
+	Redact         bool             // Redact personal data before sending text to the provider
+	Cache          cache.Store      // Caches results by document and prompt; nil disables caching
+	CacheTTL       time.Duration    // How long cached results are served
+	Prices         map[string]Price // USD per million tokens by model name prefix, for cost estimates
+	AllowedModels  []string         // Models clients may request; the defaults are always allowed
+}
+
+// DefaultConfig returns default configuration
+func DefaultConfig() Config {
+	return Config{
+		Model:         DefaultModel,
+		ResumeModel:   DefaultResumeModel,
+		Retry:         DefaultRetryPolicy(),
+		ChunkSize:     30000,
+		ChunkOverlap:  1500,
+		Concurrency:   4,
+		Redact:        true,
+		CacheTTL:      24 * time.Hour,
+		Prices:        DefaultPrices(),
+		AllowedModels: DefaultAllowedModels(),
+	}
+}
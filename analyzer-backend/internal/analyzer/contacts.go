@@ -0,0 +1,264 @@
+package analyzer
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"ea-scanner/internal/models"
+)
+
+// employerDomains are the domains of employers commonly impersonated in fake
+// offers. Domains that imitate them are flagged as lookalikes.
+var employerDomains = []string{
+	"google.com", "microsoft.com", "amazon.com", "apple.com", "meta.com", "facebook.com", "linkedin.com",
+	"netflix.com", "tesla.com", "nvidia.com", "intel.com", "cisco.com", "oracle.com", "adobe.com",
+	"salesforce.com", "ibm.com", "dell.com", "samsung.com", "walmart.com", "paypal.com", "uber.com",
+	"deloitte.com", "accenture.com", "pwc.com", "kpmg.com", "ey.com", "capgemini.com", "cognizant.com",
+	"infosys.com", "wipro.com", "tcs.com", "hcltech.com", "techmahindra.com", "jpmorgan.com",
+	"jpmorganchase.com", "goldmansachs.com", "morganstanley.com", "citi.com", "hsbc.com", "barclays.com",
+	"unilever.com", "nestle.com", "shell.com", "emirates.com", "qatarairways.com", "dhl.com", "fedex.com",
+}
+
+// relatedDomains belong to the employers above or to well-known services and
+// are never flagged as lookalikes
+var relatedDomains = map[string]bool{
+	"googlemail.com": true, "gmail.com": true, "youtube.com": true, "amazonaws.com": true,
+	"microsoftonline.com": true, "office.com": true, "outlook.com": true, "live.com": true, "icloud.com": true,
+	"metacareers.com": true, "oraclecloud.com": true,
+	"myworkdayjobs.com": true, "greenhouse.io": true, "lever.co": true, "smartrecruiters.com": true,
+}
+
+// freemailDomains are free email providers, which employers do not use to
+// send offers
+var freemailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "yahoo.com": true, "yahoo.co.in": true, "yahoo.co.uk": true,
+	"ymail.com": true, "outlook.com": true, "hotmail.com": true, "hotmail.co.uk": true, "live.com": true,
+	"msn.com": true, "aol.com": true, "icloud.com": true, "me.com": true, "mail.com": true, "gmx.com": true,
+	"gmx.net": true, "yandex.com": true, "yandex.ru": true, "mail.ru": true, "protonmail.com": true,
+	"proton.me": true, "zoho.com": true, "zohomail.com": true, "rediffmail.com": true, "qq.com": true,
+	"163.com": true, "tutanota.com": true,
+}
+
+// shortenerDomains are URL shortening services, which hide where a link leads
+var shortenerDomains = map[string]bool{
+	"bit.ly": true, "tinyurl.com": true, "t.co": true, "goo.gl": true, "ow.ly": true, "is.gd": true,
+	"buff.ly": true, "rebrand.ly": true, "cutt.ly": true, "shorturl.at": true, "rb.gy": true, "tiny.cc": true,
+	"bit.do": true, "s.id": true, "t.ly": true, "lnkd.in": true,
+}
+
+// hrWords mark the local part of an email address as a hiring contact
+var hrWords = []string{"hr", "career", "recruit", "hiring", "job", "talent", "offer", "onboard"}
+
+// employerSuffixes are words scammers append to an employer's name when
+// registering a domain for it, as in amazoncareers.com
+var employerSuffixes = []string{"careers", "career", "jobs", "job", "hr", "hiring", "recruit", "recruitment", "talent", "offer", "group", "global", "inc", "corp"}
+
+// homoglyphs maps characters and pairs that look alike to one form, so that
+// rnicrosoft.com and g00gle.com compare equal to the domains they imitate
+var homoglyphs = strings.NewReplacer(
+	"rn", "m", "vv", "w", "0", "o", "1", "l", "i", "l", "3", "e", "5", "s", "-", "",
+	// Cyrillic and Greek letters that render like Latin ones
+	"а", "a", "е", "e", "о", "o", "р", "p", "с", "c", "х", "x", "у", "y", "і", "l", "ο", "o", "α", "a",
+)
+
+// Lookalike detection limits
+const (
+	minContainedLabel = 4 // Employer names shorter than this are not searched for inside other names
+	minTypoLabel      = 6 // Employer names shorter than this are not compared for typos
+)
+
+// checkContacts flags the contacts that imitate a known employer's domain,
+// use a free email provider for a hiring contact or as the email's sender,
+// go through a URL shortener or link to an IP address. It returns the
+// contacts with their flags set and a scam indicator per kind of red flag.
+// Checks whose category was already reported are skipped.
+func checkContacts(contacts []models.Contact, email *models.EmailHeaders, documentText string, existing ...[]models.Finding) ([]models.Contact, []models.Finding) {
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+
+	var sender string
+	if email != nil {
+		if addr, err := mail.ParseAddress(email.From); err == nil {
+			sender = strings.ToLower(addr.Address)
+		}
+	}
+
+	flagged := make([]models.Contact, len(contacts))
+	var lookalikes, freemail, shortened, ipLinks []models.Contact
+	var imitated []string
+	for i, c := range contacts {
+		c.Flags = nil
+		if c.Domain != "" {
+			if employer := imitatedEmployer(c.Domain); employer != "" {
+				c.Flags = append(c.Flags, models.FlagLookalike)
+				lookalikes = append(lookalikes, c)
+				if pair := fmt.Sprintf("%s imitates %s", c.Domain, employer); !slices.Contains(imitated, pair) {
+					imitated = append(imitated, pair)
+				}
+			}
+			if freemailDomains[c.Domain] || freemailDomains[baseDomain(c.Domain)] {
+				c.Flags = append(c.Flags, models.FlagFreemail)
+				if c.Kind == models.ContactEmail && (isHRAddress(c.Value) || strings.EqualFold(c.Value, sender)) {
+					freemail = append(freemail, c)
+				}
+			}
+			if c.Kind != models.ContactEmail && shortenerDomains[baseDomain(c.Domain)] {
+				c.Flags = append(c.Flags, models.FlagShortener)
+				shortened = append(shortened, c)
+			}
+			if c.Kind == models.ContactURL && net.ParseIP(c.Domain) != nil {
+				c.Flags = append(c.Flags, models.FlagIPLiteral)
+				ipLinks = append(ipLinks, c)
+			}
+		}
+		flagged[i] = c
+	}
+
+	var findings []models.Finding
+	add := func(category, severity, description string, matched []models.Contact) {
+		if len(matched) == 0 {
+			return
+		}
+		for _, group := range slices.Concat(existing, [][]models.Finding{findings}) {
+			for _, f := range group {
+				if strings.EqualFold(f.Category, category) {
+					return
+				}
+			}
+		}
+		findings = append(findings, models.Finding{
+			Category:    category,
+			Severity:    severity,
+			Description: description,
+			Quote:       contactQuote(matched),
+			Source:      SourceCheck,
+		})
+	}
+
+	add("Lookalike Domain", "HIGH", fmt.Sprintf("The document refers to a domain that imitates a well-known employer (%s). Scammers register names that differ from the real domain by a letter, a look-alike character or an added word such as \"careers\" so that their links and addresses pass a quick glance.",
+		strings.Join(imitated, "; ")), lookalikes)
+	add("Free Email Address for HR Contact", "MEDIUM", fmt.Sprintf("The hiring contact uses a free email account (%s). Employers send offers from their own domain; recruiters writing from Gmail, Yahoo or Outlook addresses are a common sign of a fake offer.",
+		contactValues(freemail)), freemail)
+	add("Shortened Link", "MEDIUM", fmt.Sprintf("The document contains a shortened link (%s), which hides where it leads. Employers link to their own careers site; scammers use shorteners to disguise payment pages and phishing forms.",
+		contactValues(shortened)), shortened)
+	add("IP Address Link", "HIGH", fmt.Sprintf("The document links to a bare IP address instead of a domain (%s). Legitimate employers do not; such links usually lead to phishing pages or malware hosted on a rented server.",
+		contactValues(ipLinks)), ipLinks)
+
+	return flagged, verifyFindings(findings, documentText, false)
+}
+
+// imitatedEmployer returns the employer domain that domain imitates, or "" if
+// it imitates none. The employer's own domains, including those under other
+// top-level domains, are not lookalikes.
+func imitatedEmployer(domain string) string {
+	base := baseDomain(domain)
+	if relatedDomains[base] || freemailDomains[base] || shortenerDomains[base] || net.ParseIP(domain) != nil {
+		return ""
+	}
+	label, _, _ := strings.Cut(base, ".")
+	skeleton := homoglyphs.Replace(strings.ToLower(label))
+
+	for _, employer := range employerDomains {
+		name, _, _ := strings.Cut(employer, ".")
+		if label == name {
+			return ""
+		}
+	}
+	for _, employer := range employerDomains {
+		name, _, _ := strings.Cut(employer, ".")
+		nameSkeleton := homoglyphs.Replace(name)
+		switch {
+		case skeleton == nameSkeleton:
+			return employer
+		case len(name) >= minContainedLabel && containsEmployer(label, name):
+			return employer
+		case len(name) >= minTypoLabel && editDistance(skeleton, nameSkeleton) == 1:
+			// A second edit turns names into ordinary words, as adventure
+			// is to accenture
+			return employer
+		}
+	}
+	return ""
+}
+
+// containsEmployer reports whether label combines an employer's name with a
+// hiring word, either separated by hyphens, as in amazon-hr, or appended, as
+// in amazoncareers. Employers' own service domains such as
+// google-analytics.com combine their name with other words and do not count,
+// nor do names merely inside other words, as in pineapple.
+func containsEmployer(label, name string) bool {
+	if words := strings.Split(label, "-"); slices.Contains(words, name) {
+		return slices.ContainsFunc(words, func(word string) bool {
+			return slices.Contains(employerSuffixes, word)
+		})
+	}
+	rest, ok := strings.CutPrefix(label, name)
+	return ok && slices.Contains(employerSuffixes, rest)
+}
+
+// editDistance returns the Damerau-Levenshtein distance between a and b,
+// counting a swap of adjacent characters as one edit
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+// isHRAddress reports whether the local part of an email address names a
+// hiring contact, as in hr.department@ or careers2024@
+func isHRAddress(addr string) bool {
+	local, _, _ := strings.Cut(strings.ToLower(addr), "@")
+	for _, word := range hrWords {
+		if strings.Contains(local, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// contactQuote returns the first contact that appears in the document text,
+// or "" if all of them are only hyperlink targets
+func contactQuote(contacts []models.Contact) string {
+	for _, c := range contacts {
+		if !c.Linked {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// contactValues lists the values of contacts for a description, noting those
+// that are only hyperlink targets
+func contactValues(contacts []models.Contact) string {
+	values := make([]string, len(contacts))
+	for i, c := range contacts {
+		values[i] = c.Value
+		if c.Linked {
+			values[i] += " (behind a link)"
+		}
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,33 @@
+package analyzer
+
+import "testing"
+
+func TestImitatedEmployer(t *testing.T) {
+	tests := []struct {
+		domain string
+		want   string
+	}{
+		{"google.com", ""},
+		{"mail.google.co.in", ""},
+		{"google-analytics.com", ""},
+		{"www.amazon-adsystem.com", ""},
+		{"pineapple.com", ""},
+		{"adventure.com", ""},
+		{"accentuate.com", ""},
+		{"goldmansacks.com", "goldmansachs.com"},
+		{"amazon-hr.com", "amazon.com"},
+		{"careers-microsoft.net", "microsoft.com"},
+		{"amazoncareers.com", "amazon.com"},
+		{"rnicrosoft.com", "microsoft.com"},
+		{"g00gle.com", "google.com"},
+		{"accentrue.com", "accenture.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			if got := imitatedEmployer(tt.domain); got != tt.want {
+				t.Errorf("imitatedEmployer(%q) = %q, want %q", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
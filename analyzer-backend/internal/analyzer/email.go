@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ea-scanner/internal/models"
+)
+
+// authResult matches a method verdict in an Authentication-Results header
+var authResult = regexp.MustCompile(`(?i)\b(spf|dkim|dmarc)\s*=\s*([a-z]+)`)
+
+// secondLevelLabels are labels registries use under country code domains,
+// as in example.co.uk
+var secondLevelLabels = map[string]bool{
+	"co": true, "com": true, "org": true, "net": true, "ac": true, "gov": true, "edu": true, "ltd": true, "plc": true,
+}
+
+// checkEmailHeaders returns scam indicators for an email's headers: replies
+// or bounces routed to a different domain than the sender, and failed sender
+// authentication. Checks whose category was already reported are skipped.
+func checkEmailHeaders(headers *models.EmailHeaders, existing ...[]models.Finding) []models.Finding {
+	if headers == nil {
+		return nil
+	}
+
+	var findings []models.Finding
+	add := func(category, severity, description string) {
+		for _, group := range existing {
+			for _, f := range group {
+				if strings.EqualFold(f.Category, category) {
+					return
+				}
+			}
+		}
+		findings = append(findings, models.Finding{
+			Category:    category,
+			Severity:    severity,
+			Description: description,
+			Source:      SourceCheck,
+		})
+	}
+
+	from := addressDomain(headers.From)
+	if replyTo := addressDomain(headers.ReplyTo); from != "" && replyTo != "" && baseDomain(from) != baseDomain(replyTo) {
+		add("Reply-To Mismatch", "HIGH", fmt.Sprintf("The email is sent from %s but replies go to %s. Scammers use a different reply address so that answers reach them instead of the company they impersonate.", from, replyTo))
+	}
+
+	// Only the topmost header comes from the receiving server; senders can
+	// forge the ones below it. A method with several results, such as two
+	// DKIM signatures, passes if any of them passed.
+	var matches [][]string
+	passed := map[string]bool{}
+	if len(headers.AuthenticationResults) > 0 {
+		matches = authResult.FindAllStringSubmatch(headers.AuthenticationResults[0], -1)
+		for _, m := range matches {
+			if strings.EqualFold(m[2], "pass") {
+				passed[strings.ToUpper(m[1])] = true
+			}
+		}
+	}
+
+	// Mailing services bounce to their own domain, which is harmless once
+	// DMARC has tied the message to the From domain
+	if returnPath := addressDomain(headers.ReturnPath); from != "" && returnPath != "" && baseDomain(from) != baseDomain(returnPath) && !passed["DMARC"] {
+		add("Return-Path Mismatch", "MEDIUM", fmt.Sprintf("The email claims to come from %s but was sent by %s, and the receiving server did not confirm that %s authorized it. The sender may be impersonating the company.", from, returnPath, from))
+	}
+
+	var failed, soft []string
+	for _, m := range matches {
+		method, verdict := strings.ToUpper(m[1]), strings.ToLower(m[2])
+		if passed[method] || slices.Contains(failed, method) || slices.Contains(soft, method) {
+			continue
+		}
+		switch verdict {
+		case "fail", "permerror":
+			failed = append(failed, method)
+		case "softfail":
+			soft = append(soft, method)
+		}
+	}
+	switch {
+	case len(failed) > 0:
+		add("Failed Sender Authentication", "HIGH", fmt.Sprintf("The receiving mail server could not verify the sender (%s failed). The email may not come from the domain it claims.", strings.Join(failed, ", ")))
+	case len(soft) > 0:
+		add("Failed Sender Authentication", "MEDIUM", fmt.Sprintf("The receiving mail server only weakly accepted the sender (%s softfail). The email may not come from the domain it claims.", strings.Join(soft, ", ")))
+	}
+
+	return findings
+}
+
+// addressDomain returns the lowercased domain of the first address in a
+// header value, or "" if it has none
+func addressDomain(value string) string {
+	if value == "" {
+		return ""
+	}
+	addr := value
+	if parsed, err := mail.ParseAddress(value); err == nil {
+		addr = parsed.Address
+	}
+	at := strings.LastIndex(addr, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.Trim(addr[at+1:], " >"))
+}
+
+// baseDomain approximates the registrable part of a domain, so that
+// mail.example.com and example.com compare equal
+func baseDomain(domain string) string {
+	labels := strings.Split(strings.TrimSuffix(domain, "."), ".")
+	n := 2
+	if len(labels) >= 3 && len(labels[len(labels)-1]) == 2 && secondLevelLabels[labels[len(labels)-2]] {
+		n = 3
+	}
+	if len(labels) <= n {
+		return strings.Join(labels, ".")
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
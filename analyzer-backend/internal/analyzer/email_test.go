@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"testing"
+
+	"ea-scanner/internal/models"
+)
+
+func TestCheckEmailHeadersReturnPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers models.EmailHeaders
+		want    bool // Return-Path Mismatch reported
+	}{
+		{
+			name:    "same domain",
+			headers: models.EmailHeaders{From: "HR <hr@acme.com>", ReturnPath: "<bounces@mail.acme.com>"},
+		},
+		{
+			name:    "different domain",
+			headers: models.EmailHeaders{From: "HR <hr@acme.com>", ReturnPath: "<jobs@acme-careers.top>"},
+			want:    true,
+		},
+		{
+			name: "different domain with dmarc pass",
+			headers: models.EmailHeaders{
+				From:                  "HR <hr@acme.com>",
+				ReturnPath:            "<bounce-123@sendgrid.net>",
+				AuthenticationResults: []string{"mx.example.net; spf=pass smtp.mailfrom=sendgrid.net; dmarc=pass header.from=acme.com"},
+			},
+		},
+		{
+			name:    "null return path",
+			headers: models.EmailHeaders{From: "HR <hr@acme.com>", ReturnPath: "<>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := false
+			for _, f := range checkEmailHeaders(&tt.headers) {
+				if f.Category == "Return-Path Mismatch" {
+					got = true
+				}
+			}
+			if got != tt.want {
+				t.Errorf("Return-Path Mismatch reported = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FakeProvider is a deterministic Provider for tests. It replays Responses in
+// order and records every request it receives.
+type FakeProvider struct {
+	Responses []string // Returned in order; the last one repeats once exhausted
+	Errors    []error  // Optional per-call errors, matched by call index
+	Usage     Usage    // Reported for every successful call
+
+	mu       sync.Mutex
+	served   int
+	Requests []GenerateRequest
+}
+
+// NewFakeProvider creates a FakeProvider that replays the given responses
+func NewFakeProvider(responses ...string) *FakeProvider {
+	return &FakeProvider{Responses: responses}
+}
+
+// Name returns the provider name
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// RequiresAPIKey reports whether callers must supply an API key
+func (p *FakeProvider) RequiresAPIKey() bool {
+	return false
+}
+
+// GenerateText returns the next canned response
+func (p *FakeProvider) GenerateText(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	return p.next(ctx, req)
+}
+
+// GenerateJSON returns the next canned response
+func (p *FakeProvider) GenerateJSON(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	return p.next(ctx, req)
+}
+
+// Calls returns the number of requests received so far
+func (p *FakeProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.Requests)
+}
+
+func (p *FakeProvider) next(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	call := len(p.Requests)
+	p.Requests = append(p.Requests, *req)
+
+	if call < len(p.Errors) && p.Errors[call] != nil {
+		return nil, p.Errors[call]
+	}
+	if len(p.Responses) == 0 {
+		return nil, fmt.Errorf("fake provider has no responses")
+	}
+
+	// Errored calls do not consume a response
+	text := p.Responses[len(p.Responses)-1]
+	if p.served < len(p.Responses) {
+		text = p.Responses[p.served]
+	}
+	p.served++
+
+	return &GenerateResponse{Text: text, Model: req.Model, Usage: p.Usage}, nil
+}
@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFakeProvider(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tests := []struct {
+		name      string
+		responses []string
+		errors    []error
+		calls     int
+		want      []string // Text per call, "" for an error
+		wantErr   []error
+	}{
+		{
+			name:      "replays in order",
+			responses: []string{"a", "b"},
+			calls:     2,
+			want:      []string{"a", "b"},
+			wantErr:   []error{nil, nil},
+		},
+		{
+			name:      "repeats last response",
+			responses: []string{"a", "b"},
+			calls:     4,
+			want:      []string{"a", "b", "b", "b"},
+			wantErr:   []error{nil, nil, nil, nil},
+		},
+		{
+			name:      "errors do not consume responses",
+			responses: []string{"a", "b"},
+			errors:    []error{errBoom, nil, errBoom},
+			calls:     4,
+			want:      []string{"", "a", "", "b"},
+			wantErr:   []error{errBoom, nil, errBoom, nil},
+		},
+		{
+			name:    "no responses",
+			calls:   1,
+			want:    []string{""},
+			wantErr: []error{errors.New("any")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewFakeProvider(tt.responses...)
+			provider.Errors = tt.errors
+			provider.Usage = Usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}
+
+			for i := range tt.calls {
+				req := &GenerateRequest{Model: "fake-model", Prompt: "prompt"}
+				resp, err := provider.GenerateJSON(context.Background(), req)
+				switch {
+				case tt.wantErr[i] == nil:
+					if err != nil {
+						t.Fatalf("call %d: error = %v", i, err)
+					}
+					if resp.Text != tt.want[i] || resp.Model != req.Model || resp.Usage != provider.Usage {
+						t.Errorf("call %d: got %+v, want text %q, model %q and the configured usage", i, *resp, tt.want[i], req.Model)
+					}
+				case errors.Is(tt.wantErr[i], errBoom):
+					if !errors.Is(err, errBoom) {
+						t.Errorf("call %d: error = %v, want %v", i, err, errBoom)
+					}
+				default:
+					if err == nil {
+						t.Errorf("call %d: succeeded, want error", i)
+					}
+				}
+			}
+
+			if provider.Calls() != tt.calls {
+				t.Errorf("Calls() = %d, want %d", provider.Calls(), tt.calls)
+			}
+		})
+	}
+}
+
+func TestFakeProviderCanceled(t *testing.T) {
+	provider := NewFakeProvider("a")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := provider.GenerateText(ctx, &GenerateRequest{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("GenerateText() error = %v, want context.Canceled", err)
+	}
+	if provider.Calls() != 0 {
+		t.Errorf("Calls() = %d, want 0 for a canceled call", provider.Calls())
+	}
+}
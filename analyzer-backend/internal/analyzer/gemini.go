@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/genai"
+)
+
+// GeminiProvider calls the Gemini API with the caller's API key
+type GeminiProvider struct {
+	baseURL string // API endpoint override; empty for the public Gemini API
+}
+
+// NewGeminiProvider creates a new GeminiProvider
+func NewGeminiProvider() *GeminiProvider {
+	return &GeminiProvider{}
+}
+
+// Name returns the provider name
+func (p *GeminiProvider) Name() string {
+	return "gemini"
+}
+
+// RequiresAPIKey reports whether callers must supply an API key
+// (Gemini calls are billed to the caller's key)
+func (p *GeminiProvider) RequiresAPIKey() bool {
+	return true
+}
+
+// GenerateText generates free-form text
+func (p *GeminiProvider) GenerateText(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	return p.generate(ctx, req, nil)
+}
+
+// GenerateJSON generates a JSON response using Gemini's structured output mode
+func (p *GeminiProvider) GenerateJSON(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	config := &genai.GenerateContentConfig{
+		ResponseMIMEType: "application/json",
+	}
+	if req.Schema != nil {
+		config.ResponseJsonSchema = req.Schema
+	}
+	return p.generate(ctx, req, config)
+}
+
+func (p *GeminiProvider) generate(ctx context.Context, req *GenerateRequest, config *genai.GenerateContentConfig) (*GenerateResponse, error) {
+	if req.APIKey == "" {
+		return nil, fmt.Errorf("Gemini API key is required")
+	}
+
+	// Create client with user's API key
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:      req.APIKey,
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: p.baseURL},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+
+	resp, err := client.Models.GenerateContent(ctx, req.Model, genai.Text(req.Prompt), config)
+	if err != nil {
+		var apiErr genai.APIError
+		if errors.As(err, &apiErr) {
+			return nil, &ProviderError{Provider: p.Name(), StatusCode: apiErr.Code, Message: apiErr.Message}
+		}
+		return nil, fmt.Errorf("failed to generate content: %w", err)
+	}
+
+	// Extract text response
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("empty response from Gemini")
+	}
+
+	model := resp.ModelVersion
+	if model == "" {
+		model = req.Model
+	}
+
+	var usage Usage
+	if meta := resp.UsageMetadata; meta != nil {
+		usage = Usage{
+			PromptTokens:     int(meta.PromptTokenCount),
+			CompletionTokens: int(meta.CandidatesTokenCount + meta.ThoughtsTokenCount),
+			TotalTokens:      int(meta.TotalTokenCount),
+		}
+	}
+
+	return &GenerateResponse{
+		Text:  resp.Text(),
+		Model: model,
+		Usage: usage,
+	}, nil
+}
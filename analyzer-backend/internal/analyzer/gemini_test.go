@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGeminiProviderGenerate(t *testing.T) {
+	schema := &Schema{Type: "object"}
+
+	tests := []struct {
+		name       string
+		json       bool
+		req        GenerateRequest
+		status     int
+		body       string
+		wantMIME   string // generationConfig.responseMimeType sent upstream
+		wantSchema bool
+		want       *GenerateResponse
+		wantStatus int // ProviderError status, -1 for a plain error, 0 for success
+	}{
+		{
+			name:       "json with schema",
+			json:       true,
+			req:        GenerateRequest{APIKey: "caller-key", Model: DefaultModel, Prompt: "analyze", Schema: schema},
+			status:     http.StatusOK,
+			body:       `{"modelVersion":"gemini-2.0-flash-001","candidates":[{"content":{"role":"model","parts":[{"text":"{\"ok\":true}"}]}}],"usageMetadata":{"promptTokenCount":10,"candidatesTokenCount":4,"thoughtsTokenCount":2,"totalTokenCount":16}}`,
+			wantMIME:   "application/json",
+			wantSchema: true,
+			want:       &GenerateResponse{Text: `{"ok":true}`, Model: "gemini-2.0-flash-001", Usage: Usage{PromptTokens: 10, CompletionTokens: 6, TotalTokens: 16}},
+		},
+		{
+			name:   "text",
+			req:    GenerateRequest{APIKey: "caller-key", Model: DefaultModel, Prompt: "hello"},
+			status: http.StatusOK,
+			body:   `{"candidates":[{"content":{"role":"model","parts":[{"text":"hi"}]}}]}`,
+			want:   &GenerateResponse{Text: "hi", Model: DefaultModel},
+		},
+		{
+			name:       "unavailable",
+			req:        GenerateRequest{APIKey: "caller-key", Model: DefaultModel, Prompt: "hello"},
+			status:     http.StatusServiceUnavailable,
+			body:       `{"error":{"code":503,"message":"overloaded","status":"UNAVAILABLE"}}`,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "no candidates",
+			req:        GenerateRequest{APIKey: "caller-key", Model: DefaultModel, Prompt: "hello"},
+			status:     http.StatusOK,
+			body:       `{"candidates":[]}`,
+			wantStatus: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				path string
+				key  string
+				body struct {
+					Contents []struct {
+						Parts []struct {
+							Text string `json:"text"`
+						} `json:"parts"`
+					} `json:"contents"`
+					GenerationConfig struct {
+						ResponseMIMEType   string `json:"responseMimeType"`
+						ResponseJSONSchema any    `json:"responseJsonSchema"`
+					} `json:"generationConfig"`
+				}
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got.path = r.URL.Path
+				got.key = r.Header.Get("x-goog-api-key")
+				if err := json.NewDecoder(r.Body).Decode(&got.body); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := &GeminiProvider{baseURL: server.URL + "/"}
+			generate := provider.GenerateText
+			if tt.json {
+				generate = provider.GenerateJSON
+			}
+			resp, err := generate(context.Background(), &tt.req)
+
+			if want := "/v1beta/models/" + tt.req.Model + ":generateContent"; got.path != want {
+				t.Errorf("path = %q, want %q", got.path, want)
+			}
+			if got.key != tt.req.APIKey {
+				t.Errorf("API key sent = %q, want %q", got.key, tt.req.APIKey)
+			}
+			if len(got.body.Contents) != 1 || len(got.body.Contents[0].Parts) != 1 || got.body.Contents[0].Parts[0].Text != tt.req.Prompt {
+				t.Errorf("contents = %+v, want the prompt as one part", got.body.Contents)
+			}
+			if mime := got.body.GenerationConfig.ResponseMIMEType; mime != tt.wantMIME {
+				t.Errorf("responseMimeType = %q, want %q", mime, tt.wantMIME)
+			}
+			if hasSchema := got.body.GenerationConfig.ResponseJSONSchema != nil; hasSchema != tt.wantSchema {
+				t.Errorf("responseJsonSchema sent = %v, want %v", hasSchema, tt.wantSchema)
+			}
+
+			switch {
+			case tt.wantStatus == 0:
+				if err != nil {
+					t.Fatalf("generate() error = %v", err)
+				}
+				if *resp != *tt.want {
+					t.Errorf("generate() = %+v, want %+v", *resp, *tt.want)
+				}
+			case tt.wantStatus > 0:
+				var providerErr *ProviderError
+				if !errors.As(err, &providerErr) || providerErr.StatusCode != tt.wantStatus {
+					t.Errorf("generate() error = %v, want ProviderError with status %d", err, tt.wantStatus)
+				}
+			default:
+				if err == nil {
+					t.Errorf("generate() succeeded, want error")
+				}
+			}
+		})
+	}
+}
+
+func TestGeminiProviderRequiresAPIKey(t *testing.T) {
+	provider := NewGeminiProvider()
+	if !provider.RequiresAPIKey() {
+		t.Error("RequiresAPIKey() = false, want true")
+	}
+	if _, err := provider.GenerateText(context.Background(), &GenerateRequest{Model: DefaultModel, Prompt: "hello"}); err == nil {
+		t.Error("GenerateText() without an API key succeeded, want error")
+	}
+}
@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	"ea-scanner/internal/models"
+	"ea-scanner/internal/redact"
+)
+
+// chunkPromptPreamble tells the model it is looking at part of a document
+const chunkPromptPreamble = `You are analyzing part %d of %d of a longer document. Only report findings that are visible in this part, quoting text from this part. List an element as missing only if it is absent from this part.`
+
+// PartialResult carries the findings of one analyzed part in a StagePartial event
+type PartialResult struct {
+	Part           int              `json:"part"`
+	Parts          int              `json:"parts"`
+	ScamIndicators []models.Finding `json:"scam_indicators"`
+	RiskyClauses   []models.Finding `json:"risky_clauses"`
+}
+
+// analyzeChunks analyzes each chunk in parallel with bounded concurrency and
+// merges the per-chunk results. Chunks are cut from the redacted text when
+// red is set.
+func (a *Analyzer) analyzeChunks(ctx context.Context, apiKey, model, documentText, instructions string, chunks []chunk, red *redact.Result) (*models.AnalysisResult, error) {
+	results := make([]*models.AnalysisResult, len(chunks))
+	errs := make([]error, len(chunks))
+
+	sem := make(chan struct{}, max(a.config.Concurrency, 1))
+	var wg sync.WaitGroup
+
+	for i, c := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			prompt := fmt.Sprintf("%s\n\n"+chunkPromptPreamble+"\n\n---\n%s\n---", instructions, i+1, len(chunks), c.Text)
+			results[i], errs[i] = a.generate(ctx, apiKey, model, prompt)
+			if errs[i] != nil {
+				log.Printf("Chunk %d/%d failed: %v", i+1, len(chunks), errs[i])
+				return
+			}
+
+			a.emitPartial(ctx, i+1, len(chunks), results[i], documentText, red)
+		}()
+	}
+	wg.Wait()
+
+	return mergeChunkResults(results, errs)
+}
+
+// emitPartial reports the findings of one analyzed part as early red flags.
+// Copies are restored and verified so the originals stay untouched for merging.
+func (a *Analyzer) emitPartial(ctx context.Context, part, parts int, result *models.AnalysisResult, documentText string, red *redact.Result) {
+	indicators, clauses := slices.Clone(result.ScamIndicators), slices.Clone(result.RiskyClauses)
+	restoreFindings(indicators, red)
+	restoreFindings(clauses, red)
+	emit(ctx, Event{Stage: StagePartial, Message: fmt.Sprintf("Part %d of %d analyzed", part, parts), Data: PartialResult{
+		Part:           part,
+		Parts:          parts,
+		ScamIndicators: verifyFindings(indicators, documentText, a.config.DropUnverified),
+		RiskyClauses:   verifyFindings(clauses, documentText, a.config.DropUnverified),
+	}})
+}
+
+// mergeChunkResults reduces per-chunk results into one result. Chunks that
+// failed are reported as warnings; it is an error only if every chunk failed.
+func mergeChunkResults(results []*models.AnalysisResult, errs []error) (*models.AnalysisResult, error) {
+	merged := &models.AnalysisResult{
+		ScamIndicators:  []models.Finding{},
+		RiskyClauses:    []models.Finding{},
+		MissingElements: []string{},
+		Recommendations: []string{},
+		ChunkCount:      len(results),
+	}
+
+	var succeeded []*models.AnalysisResult
+	var firstErr error
+	for i, r := range results {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			merged.Partial = true
+			merged.Warnings = append(merged.Warnings, fmt.Sprintf("Part %d of %d could not be analyzed: %v", i+1, len(results), errs[i]))
+			continue
+		}
+		succeeded = append(succeeded, r)
+	}
+	if len(succeeded) == 0 {
+		return nil, firstErr
+	}
+
+	var top *models.AnalysisResult
+	for _, r := range succeeded {
+		merged.Attempts += r.Attempts
+		merged.Usage = addUsage(merged.Usage, r.Usage)
+		merged.ScamIndicators = mergeFindings(merged.ScamIndicators, r.ScamIndicators)
+		merged.RiskyClauses = mergeFindings(merged.RiskyClauses, r.RiskyClauses)
+		merged.Recommendations = appendUnique(merged.Recommendations, r.Recommendations...)
+		merged.Terms = mergeTerms(merged.Terms, r.Terms)
+		if top == nil || r.RiskScore > top.RiskScore {
+			top = r
+		}
+	}
+
+	// An element is missing from the document only if every part lacked it
+	for _, element := range succeeded[0].MissingElements {
+		missingEverywhere := true
+		for _, r := range succeeded[1:] {
+			if !containsFold(r.MissingElements, element) {
+				missingEverywhere = false
+				break
+			}
+		}
+		if missingEverywhere {
+			merged.MissingElements = appendUnique(merged.MissingElements, element)
+		}
+	}
+
+	merged.Summary = top.Summary
+	merged.RiskScore = scoreFindings(merged.ScamIndicators, merged.RiskyClauses)
+	merged.RiskLevel = riskLevelForScore(merged.RiskScore)
+
+	return merged, nil
+}
+
+// mergeFindings adds findings to existing, collapsing near-identical quotes
+// into the more severe finding
+func mergeFindings(existing, findings []models.Finding) []models.Finding {
+	for _, f := range findings {
+		duplicate := false
+		for i, e := range existing {
+			if similarQuotes(e.Quote, f.Quote) {
+				duplicate = true
+				if severityRank(f.Severity) > severityRank(e.Severity) {
+					existing[i] = f
+				}
+				break
+			}
+		}
+		if !duplicate {
+			existing = append(existing, f)
+		}
+	}
+	return existing
+}
+
+// severityRank orders severities from LOW (0) to CRITICAL (3)
+func severityRank(severity string) int {
+	return slices.Index(severityOrder, severity)
+}
+
+// appendUnique appends values not already present (case-insensitively)
+func appendUnique(values []string, add ...string) []string {
+	for _, v := range add {
+		if !containsFold(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(s)) {
+			return true
+		}
+	}
+	return false
+}
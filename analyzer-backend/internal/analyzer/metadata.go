@@ -0,0 +1,197 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"ea-scanner/internal/models"
+	"ea-scanner/internal/redact"
+)
+
+// Timing of a document created for the email that carries it
+const (
+	createdBeforeSending = 30 * time.Minute // Creation this close to sending is suspicious
+	clockSkew            = 5 * time.Minute  // Creation after sending allowed for clock differences
+)
+
+// onlineEditors are producers and creators recorded by web-based PDF
+// editors, matched case-insensitively
+var onlineEditors = []string{
+	"ilovepdf", "smallpdf", "sejda", "pdfescape", "pdffiller", "pdf24", "pdfcandy", "soda pdf", "sodapdf",
+	"dochub", "pdfsimpli", "formswift", "pdf2go", "online2pdf", "hipdf", "docfly", "pdfzorro", "lightpdf",
+}
+
+// organizationWords mark an author field as an organization, a role or a
+// default account rather than a person
+var organizationWords = map[string]bool{
+	"inc": true, "ltd": true, "llc": true, "llp": true, "plc": true, "pvt": true, "gmbh": true, "limited": true,
+	"corp": true, "corporation": true, "company": true, "co": true, "group": true, "bank": true, "university": true,
+	"services": true, "solutions": true, "technologies": true, "consulting": true, "hr": true, "team": true,
+	"recruitment": true, "careers": true, "department": true, "office": true, "admin": true, "administrator": true,
+	"user": true, "owner": true, "author": true, "unknown": true,
+}
+
+// metadataPrompt introduces document properties to the model
+const metadataPrompt = `## DOCUMENT METADATA
+The properties recorded in the file are listed below. Use them as evidence for scam indicators, such as a document created minutes before the email carrying it was sent, a private individual rather than the company as author, an online PDF editor as producer, or a modification date earlier than the creation date. Quote only the document text, never these properties.`
+
+// checkMetadata returns scam indicators for document properties: dates that
+// run backwards, online PDF editors, a personal name as author and, for
+// email attachments, creation just before the email was sent. Checks whose
+// category was already reported are skipped.
+func checkMetadata(metadata []models.DocumentMetadata, email *models.EmailHeaders, existing ...[]models.Finding) []models.Finding {
+	var findings []models.Finding
+	add := func(category, severity, description string) {
+		// Several files can trip the same check; the first one is reported
+		for _, group := range slices.Concat(existing, [][]models.Finding{findings}) {
+			for _, f := range group {
+				if strings.EqualFold(f.Category, category) {
+					return
+				}
+			}
+		}
+		findings = append(findings, models.Finding{
+			Category:    category,
+			Severity:    severity,
+			Description: description,
+			Source:      SourceCheck,
+		})
+	}
+
+	for _, meta := range metadata {
+		name := metadataSubject(meta)
+
+		if meta.Created != nil && meta.Modified != nil && meta.Modified.Before(meta.Created.Add(-time.Minute)) {
+			add("Inconsistent Document Dates", "MEDIUM", fmt.Sprintf("%s was last modified on %s, before it was created on %s. Dates that run backwards suggest the properties were edited or the file was assembled from another document.",
+				name, formatMetadataTime(*meta.Modified), formatMetadataTime(*meta.Created)))
+		}
+
+		if tool := onlineEditor(meta); tool != "" {
+			add("Edited With Online PDF Tool", "MEDIUM", fmt.Sprintf("%s was produced with %s, an online PDF editor. Employers issue offer letters from their HR systems or office software; online editors are often used to alter a genuine letter or forge a new one.", name, tool))
+		}
+
+		if email != nil && email.Date != nil && meta.Created != nil {
+			if lead := email.Date.Sub(*meta.Created); lead < createdBeforeSending && lead > -clockSkew {
+				add("Document Created Just Before Sending", "MEDIUM", fmt.Sprintf("%s was created %s before the email was sent. Genuine offer letters are prepared ahead of time; a letter made minutes before sending is typical of mass-produced fake offers.", name, formatLead(lead)))
+			}
+		}
+
+		if meta.Company == "" && isPersonalName(meta.Author) {
+			add("Personal Author Name", "LOW", fmt.Sprintf("%s lists %q as its author and records no company. Offer letters generated by an employer usually carry the company or its HR system; a personal name can point to a letter written by an individual posing as the employer.", name, meta.Author))
+		}
+	}
+
+	return findings
+}
+
+// metadataNote returns the document properties for the model, or "" if
+// there are none. When redacting, authors with personal names are described
+// instead of named, and personal data in the other properties is masked.
+func metadataNote(metadata []models.DocumentMetadata, redacting bool) string {
+	var lines []string
+	for _, meta := range metadata {
+		var props []string
+		prop := func(label, value string, person bool) {
+			if value == "" {
+				return
+			}
+			switch {
+			case redacting && person && isPersonalName(value):
+				props = append(props, label+": (a personal name)")
+			case redacting:
+				props = append(props, fmt.Sprintf("%s: %q", label, redact.Mask(value)))
+			default:
+				props = append(props, fmt.Sprintf("%s: %q", label, value))
+			}
+		}
+		prop("title", meta.Title, false)
+		prop("author", meta.Author, true)
+		prop("last modified by", meta.LastModifiedBy, true)
+		prop("company", meta.Company, false)
+		prop("creator", meta.Creator, false)
+		prop("producer", meta.Producer, false)
+		if meta.Created != nil {
+			props = append(props, "created: "+meta.Created.Format(time.RFC3339))
+		}
+		if meta.Modified != nil {
+			props = append(props, "modified: "+meta.Modified.Format(time.RFC3339))
+		}
+		if len(props) > 0 {
+			subject := metadataSubject(meta)
+			if redacting {
+				subject = redact.Mask(subject)
+			}
+			lines = append(lines, fmt.Sprintf("- %s: %s", subject, strings.Join(props, "; ")))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return metadataPrompt + "\n" + strings.Join(lines, "\n")
+}
+
+// metadataSubject names the file the metadata belongs to
+func metadataSubject(meta models.DocumentMetadata) string {
+	if meta.Source != "" {
+		return fmt.Sprintf("The attachment %q", meta.Source)
+	}
+	return "The document"
+}
+
+// onlineEditor returns the creator or producer if it is an online PDF
+// editor, or ""
+func onlineEditor(meta models.DocumentMetadata) string {
+	for _, tool := range []string{meta.Producer, meta.Creator} {
+		lower := strings.ToLower(tool)
+		for _, editor := range onlineEditors {
+			if strings.Contains(lower, editor) {
+				return tool
+			}
+		}
+	}
+	return ""
+}
+
+// isPersonalName reports whether value looks like a person's name: two to
+// four capitalized words of letters, none of which names an organization,
+// role or default account
+func isPersonalName(value string) bool {
+	words := strings.Fields(value)
+	if len(words) < 2 || len(words) > 4 {
+		return false
+	}
+	for _, word := range words {
+		if organizationWords[strings.ToLower(strings.Trim(word, ".,"))] {
+			return false
+		}
+		for i, r := range word {
+			switch {
+			case i == 0 && !unicode.IsUpper(r):
+				return false
+			case !unicode.IsLetter(r) && !strings.ContainsRune(".'-", r):
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// formatMetadataTime formats a metadata date for descriptions
+func formatMetadataTime(t time.Time) string {
+	return t.Format("2 Jan 2006 15:04 MST")
+}
+
+// formatLead describes how long before sending a document was created
+func formatLead(lead time.Duration) string {
+	switch minutes := int(lead.Round(time.Minute).Minutes()); {
+	case minutes <= 0:
+		return "less than a minute"
+	case minutes == 1:
+		return "1 minute"
+	default:
+		return fmt.Sprintf("%d minutes", minutes)
+	}
+}
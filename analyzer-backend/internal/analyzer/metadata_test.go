@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"ea-scanner/internal/models"
+)
+
+func TestMetadataNoteRedaction(t *testing.T) {
+	metadata := []models.DocumentMetadata{{
+		Title:   "Offer for Mr. Rahul Verma, rahul.verma@example.com",
+		Author:  "Priya Nair",
+		Company: "Acme Corp",
+	}}
+
+	tests := []struct {
+		name      string
+		redacting bool
+		want      []string
+		unwanted  []string
+	}{
+		{
+			name:      "redacting",
+			redacting: true,
+			want:      []string{"[NAME]", "[EMAIL]", "author: (a personal name)", `company: "Acme Corp"`},
+			unwanted:  []string{"Rahul", "Verma", "rahul.verma@example.com", "Priya", "[NAME_1]"},
+		},
+		{
+			name:     "not redacting",
+			want:     []string{"Rahul Verma", "rahul.verma@example.com", `author: "Priya Nair"`},
+			unwanted: []string{"[NAME]", "[EMAIL]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := metadataNote(metadata, tt.redacting)
+			for _, want := range tt.want {
+				if !strings.Contains(note, want) {
+					t.Errorf("metadataNote() = %q, want it to contain %q", note, want)
+				}
+			}
+			for _, value := range tt.unwanted {
+				if strings.Contains(note, value) {
+					t.Errorf("metadataNote() = %q, contains %q", note, value)
+				}
+			}
+		})
+	}
+}
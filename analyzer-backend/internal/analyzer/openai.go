@@ -0,0 +1,169 @@
+package analyzer
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIConfig configures an OpenAI-compatible chat completions endpoint
+type OpenAIConfig struct {
+	BaseURL string        // e.g. "http://localhost:11434/v1" for a self-hosted server
+	APIKey  string        // Server-side key, if the endpoint needs one
+	Model   string        // Model served by the endpoint, used when a request names none
+	Timeout time.Duration // HTTP timeout per call
+}
+
+// OpenAIProvider talks to any server implementing the OpenAI chat completions API
+// (vLLM, Ollama, llama.cpp, LocalAI, ...)
+type OpenAIProvider struct {
+	config OpenAIConfig
+	client *http.Client
+}
+
+// NewOpenAIProvider creates a new OpenAIProvider
+func NewOpenAIProvider(config OpenAIConfig) (*OpenAIProvider, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("OpenAI-compatible base URL is required")
+	}
+	if config.Model == "" {
+		// A self-hosted server does not know the default Gemini model names
+		return nil, fmt.Errorf("OpenAI-compatible model name is required")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 5 * time.Minute
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	return &OpenAIProvider{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Name returns the provider name
+func (p *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+// RequiresAPIKey reports whether callers must supply an API key
+// (self-hosted servers usually run without auth)
+func (p *OpenAIProvider) RequiresAPIKey() bool {
+	return false
+}
+
+// GenerateText generates free-form text
+func (p *OpenAIProvider) GenerateText(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	return p.generate(ctx, req, nil)
+}
+
+// GenerateJSON generates a response in JSON schema mode, or plain JSON object
+// mode when no schema is given
+func (p *OpenAIProvider) GenerateJSON(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	if req.Schema == nil {
+		return p.generate(ctx, req, map[string]any{"type": "json_object"})
+	}
+	return p.generate(ctx, req, map[string]any{
+		"type": "json_schema",
+		"json_schema": map[string]any{
+			"name":   "response",
+			"schema": req.Schema,
+		},
+	})
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model          string         `json:"model"`
+	Messages       []chatMessage  `json:"messages"`
+	ResponseFormat map[string]any `json:"response_format,omitempty"`
+}
+
+type chatCompletionResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (p *OpenAIProvider) generate(ctx context.Context, req *GenerateRequest, responseFormat map[string]any) (*GenerateResponse, error) {
+	model := cmp.Or(req.Model, p.config.Model)
+
+	body, err := json.Marshal(chatCompletionRequest{
+		Model:          model,
+		Messages:       []chatMessage{{Role: "user", Content: req.Prompt}},
+		ResponseFormat: responseFormat,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	// The caller's key was issued for another service and is never forwarded
+	if p.config.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", p.config.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ProviderError{Provider: p.Name(), StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(respBody))}
+	}
+
+	var completion chatCompletionResponse
+	if err := json.Unmarshal(respBody, &completion); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if completion.Error != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: completion.Error.Message}
+	}
+	if len(completion.Choices) == 0 || completion.Choices[0].Message.Content == "" {
+		return nil, fmt.Errorf("empty response from %s", p.config.BaseURL)
+	}
+
+	if completion.Model != "" {
+		model = completion.Model
+	}
+
+	return &GenerateResponse{
+		Text:  completion.Choices[0].Message.Content,
+		Model: model,
+		Usage: Usage{
+			PromptTokens:     completion.Usage.PromptTokens,
+			CompletionTokens: completion.Usage.CompletionTokens,
+			TotalTokens:      completion.Usage.TotalTokens,
+		},
+	}, nil
+}
@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewOpenAIProviderRequiresBaseURLAndModel(t *testing.T) {
+	tests := []struct {
+		name   string
+		config OpenAIConfig
+	}{
+		{"no base URL", OpenAIConfig{Model: "llama3"}},
+		{"no model", OpenAIConfig{BaseURL: "http://localhost:11434/v1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewOpenAIProvider(tt.config); err == nil {
+				t.Fatal("NewOpenAIProvider() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestOpenAIProviderGenerate(t *testing.T) {
+	schema := &Schema{Type: "object"}
+
+	tests := []struct {
+		name       string
+		serverKey  string
+		json       bool
+		req        GenerateRequest
+		status     int
+		body       string
+		wantFormat string // response_format.type sent upstream, "" for none
+		wantAuth   string
+		want       *GenerateResponse
+		wantStatus int // ProviderError status, -1 for a plain error, 0 for success
+	}{
+		{
+			name:       "json schema with server key",
+			serverKey:  "server-key",
+			json:       true,
+			req:        GenerateRequest{APIKey: "caller-key", Prompt: "analyze", Schema: schema},
+			status:     http.StatusOK,
+			body:       `{"model":"llama3:8b","choices":[{"message":{"role":"assistant","content":"{\"ok\":true}"}}],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`,
+			wantFormat: "json_schema",
+			wantAuth:   "Bearer server-key",
+			want:       &GenerateResponse{Text: `{"ok":true}`, Model: "llama3:8b", Usage: Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+		},
+		{
+			name:       "json object without server key",
+			json:       true,
+			req:        GenerateRequest{APIKey: "caller-key", Prompt: "analyze"},
+			status:     http.StatusOK,
+			body:       `{"choices":[{"message":{"role":"assistant","content":"{}"}}]}`,
+			wantFormat: "json_object",
+			want:       &GenerateResponse{Text: "{}", Model: "llama3"},
+		},
+		{
+			name:   "text with requested model and no key",
+			req:    GenerateRequest{Model: "qwen2.5", Prompt: "hello"},
+			status: http.StatusOK,
+			body:   `{"choices":[{"message":{"role":"assistant","content":"hi"}}]}`,
+			want:   &GenerateResponse{Text: "hi", Model: "qwen2.5"},
+		},
+		{
+			name:       "rate limited",
+			req:        GenerateRequest{Prompt: "hello"},
+			status:     http.StatusTooManyRequests,
+			body:       `slow down`,
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "error object",
+			req:        GenerateRequest{Prompt: "hello"},
+			status:     http.StatusOK,
+			body:       `{"error":{"message":"model not loaded"}}`,
+			wantStatus: -1,
+		},
+		{
+			name:       "no choices",
+			req:        GenerateRequest{Prompt: "hello"},
+			status:     http.StatusOK,
+			body:       `{"choices":[]}`,
+			wantStatus: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				path string
+				auth string
+				body chatCompletionRequest
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got.path = r.URL.Path
+				got.auth = r.Header.Get("Authorization")
+				if err := json.NewDecoder(r.Body).Decode(&got.body); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider, err := NewOpenAIProvider(OpenAIConfig{BaseURL: server.URL + "/v1/", APIKey: tt.serverKey, Model: "llama3"})
+			if err != nil {
+				t.Fatalf("NewOpenAIProvider() error = %v", err)
+			}
+
+			generate := provider.GenerateText
+			if tt.json {
+				generate = provider.GenerateJSON
+			}
+			resp, err := generate(context.Background(), &tt.req)
+
+			if got.path != "/v1/chat/completions" {
+				t.Errorf("path = %q, want /v1/chat/completions", got.path)
+			}
+			if want := cmp.Or(tt.req.Model, "llama3"); got.body.Model != want {
+				t.Errorf("model sent = %q, want %q", got.body.Model, want)
+			}
+			if len(got.body.Messages) != 1 || got.body.Messages[0].Content != tt.req.Prompt {
+				t.Errorf("messages = %+v, want the prompt as one user message", got.body.Messages)
+			}
+			if format, _ := got.body.ResponseFormat["type"].(string); format != tt.wantFormat {
+				t.Errorf("response_format type = %q, want %q", format, tt.wantFormat)
+			}
+			if got.auth != tt.wantAuth {
+				t.Errorf("Authorization = %q, want %q", got.auth, tt.wantAuth)
+			}
+
+			switch {
+			case tt.wantStatus == 0:
+				if err != nil {
+					t.Fatalf("generate() error = %v", err)
+				}
+				if *resp != *tt.want {
+					t.Errorf("generate() = %+v, want %+v", *resp, *tt.want)
+				}
+			case tt.wantStatus > 0:
+				var providerErr *ProviderError
+				if !errors.As(err, &providerErr) || providerErr.StatusCode != tt.wantStatus {
+					t.Errorf("generate() error = %v, want ProviderError with status %d", err, tt.wantStatus)
+				}
+			default:
+				if err == nil {
+					t.Errorf("generate() succeeded, want error")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"unicode/utf8"
+
+	"ea-scanner/internal/models"
+	"ea-scanner/internal/rules"
+)
+
+// Finding sources
+const (
+	SourceLLM   = "llm"
+	SourceRule  = "rule"
+	SourceCheck = "check"
+)
+
+// findingsFromHits converts rule hits into findings. Hits of absent rules
+// have no quote and are not verified.
+func findingsFromHits(hits []rules.Hit, text string) []models.Finding {
+	findings := make([]models.Finding, 0, len(hits))
+	for _, hit := range hits {
+		f := models.Finding{
+			Category:    hit.Rule.Category,
+			Severity:    hit.Rule.Severity,
+			Description: hit.Rule.Explanation,
+			Source:      SourceRule,
+			RuleID:      hit.Rule.ID,
+		}
+		if hit.Pack != nil {
+			f.Jurisdiction = hit.Pack.Jurisdiction
+		}
+		if hit.Start >= 0 {
+			start := utf8.RuneCountInString(text[:hit.Start])
+			f.Quote = hit.Quote
+			f.StartOffset = start
+			f.EndOffset = start + utf8.RuneCountInString(hit.Quote)
+			f.Page = pageAt(text, hit.Start)
+			f.Verified = true
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// hitsOfKind returns the hits whose rule is of the given kind
+func hitsOfKind(hits []rules.Hit, kind string) []rules.Hit {
+	var matched []rules.Hit
+	for _, hit := range hits {
+		if hit.Rule.Kind == kind {
+			matched = append(matched, hit)
+		}
+	}
+	return matched
+}
+
+// citeRules keeps the rule IDs the model cited from the jurisdiction pack and
+// clears any others
+func citeRules(findings []models.Finding, pack *rules.Pack) {
+	for i := range findings {
+		f := &findings[i]
+		if f.Source != SourceLLM || f.RuleID == "" {
+			continue
+		}
+		if pack != nil && packHasID(pack, f.RuleID) {
+			f.Jurisdiction = pack.Jurisdiction
+		} else {
+			f.RuleID = ""
+		}
+	}
+}
+
+// packHasID reports whether the pack defines a rule or term check with the ID
+func packHasID(pack *rules.Pack, id string) bool {
+	for _, rule := range pack.Rules {
+		if rule.ID == id {
+			return true
+		}
+	}
+	for _, check := range pack.TermChecks {
+		if check.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeRuleFindings adds rule findings to the model's findings, skipping any
+// rule hit whose text the model already quoted or whose rule it cited
+func mergeRuleFindings(llm, rule []models.Finding) []models.Finding {
+	merged := llm
+	for _, r := range rule {
+		duplicate := false
+		for _, f := range llm {
+			if (f.Verified && r.StartOffset < f.EndOffset && f.StartOffset < r.EndOffset) || f.RuleID == r.RuleID {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// markSource sets the source of every finding that does not have one
+func markSource(findings []models.Finding, source string) {
+	for i := range findings {
+		if findings[i].Source == "" {
+			findings[i].Source = source
+		}
+	}
+}
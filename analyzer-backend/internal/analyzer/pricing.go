@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"math"
+	"strings"
+
+	"ea-scanner/internal/models"
+)
+
+// Price is a model's list price in USD per million tokens
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// DefaultPrices returns list prices for common models. Keys match model names
+// by prefix, so "gemini-2.0-flash" also prices "gemini-2.0-flash-001".
+func DefaultPrices() map[string]Price {
+	return map[string]Price{
+		"gemini-2.0-flash":      {Input: 0.10, Output: 0.40},
+		"gemini-2.0-flash-lite": {Input: 0.075, Output: 0.30},
+		"gemini-2.5-flash":      {Input: 0.30, Output: 2.50},
+		"gemini-2.5-flash-lite": {Input: 0.10, Output: 0.40},
+		"gemini-2.5-pro":        {Input: 1.25, Output: 10.00},
+	}
+}
+
+// estimateCost prices usage with the longest entry in prices that prefixes
+// the model name. Models without a price cost 0.
+func estimateCost(prices map[string]Price, model string, usage Usage) float64 {
+	price, _ := lookupModel(prices, model)
+	cost := (float64(usage.PromptTokens)*price.Input + float64(usage.CompletionTokens)*price.Output) / 1e6
+	return math.Round(cost*1e6) / 1e6
+}
+
+// lookupModel returns the entry whose key is the longest prefix of the model
+// name, ignoring a "models/" prefix
+func lookupModel[V any](table map[string]V, model string) (V, bool) {
+	model = strings.TrimPrefix(model, "models/")
+
+	var value V
+	longest := -1
+	for name, v := range table {
+		if strings.HasPrefix(model, name) && len(name) > longest {
+			value, longest = v, len(name)
+		}
+	}
+	return value, longest >= 0
+}
+
+// usageFor reports a generation's usage with its estimated cost
+func (c Config) usageFor(gen generation) *models.Usage {
+	return &models.Usage{
+		Model:            gen.model,
+		PromptTokens:     gen.usage.PromptTokens,
+		CompletionTokens: gen.usage.CompletionTokens,
+		TotalTokens:      gen.usage.TotalTokens,
+		EstimatedCostUSD: estimateCost(c.Prices, gen.model, gen.usage),
+	}
+}
+
+// addUsage adds the tokens and cost of other to u; latency is left to the caller
+func addUsage(u, other *models.Usage) *models.Usage {
+	if other == nil {
+		return u
+	}
+	if u == nil {
+		copied := *other
+		return &copied
+	}
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.EstimatedCostUSD = math.Round((u.EstimatedCostUSD+other.EstimatedCostUSD)*1e6) / 1e6
+	return u
+}
@@ -0,0 +1,38 @@
+package analyzer
+
+import "context"
+
+// Progress stages, in the order they are emitted
+const (
+	StageParsed     = "parsed"      // Document parsed and normalized
+	StageRules      = "rules"       // Rule pre-scan finished
+	StageLLMStarted = "llm_started" // Model analysis started
+	StagePartial    = "partial"     // Findings from one analyzed part of the document
+	StageResult     = "result"      // Final result
+	StageError      = "error"       // Analysis failed
+)
+
+// Event is a progress update emitted during analysis
+type Event struct {
+	Stage   string `json:"stage"`
+	Message string `json:"message,omitempty"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// ProgressFunc receives progress events. It may be called from several
+// goroutines at once.
+type ProgressFunc func(Event)
+
+type progressKey struct{}
+
+// WithProgress returns a context that delivers analysis progress to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// emit sends an event to the context's ProgressFunc, if any
+func emit(ctx context.Context, event Event) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(event)
+	}
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+)
+
+// GenerateRequest describes a single prompt sent to an LLM provider
+type GenerateRequest struct {
+	APIKey string  // Caller's API key (may be empty for self-hosted providers)
+	Model  string  // Model name; providers may override it
+	Prompt string  // Full prompt text
+	Schema *Schema // Response schema for GenerateJSON (optional)
+}
+
+// GenerateResponse holds the raw output of a provider call
+type GenerateResponse struct {
+	Text  string // Generated text
+	Model string // Model that actually served the request
+	Usage Usage  // Tokens billed for the call, if the provider reports them
+}
+
+// Usage counts the tokens of one or more provider calls
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int // Output tokens, including any reasoning tokens
+	TotalTokens      int
+}
+
+// add accumulates another call's usage
+func (u *Usage) add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+}
+
+// Provider is an LLM backend that the analyzers use to generate output
+type Provider interface {
+	// Name identifies the provider (e.g. "gemini", "openai")
+	Name() string
+
+	// RequiresAPIKey reports whether callers must supply their own API key
+	RequiresAPIKey() bool
+
+	// GenerateText returns free-form text for the prompt
+	GenerateText(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error)
+
+	// GenerateJSON returns a single JSON object, constrained to req.Schema
+	// when the backend supports structured output
+	GenerateJSON(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error)
+}
+
+// ProviderError is returned by providers when the upstream API rejects a call
+type ProviderError struct {
+	Provider   string
+	StatusCode int // HTTP status code, 0 if unknown
+	Message    string
+}
+
+func (e *ProviderError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s API error (status %d): %s", e.Provider, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s API error: %s", e.Provider, e.Message)
+}
@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"ea-scanner/internal/models"
+	"ea-scanner/internal/redact"
+)
+
+// restoreResult replaces redaction placeholders in the model's output with
+// the original values
+func restoreResult(result *models.AnalysisResult, red *redact.Result) {
+	if red == nil {
+		return
+	}
+
+	restoreFindings(result.ScamIndicators, red)
+	restoreFindings(result.RiskyClauses, red)
+	restoreStrings(result.MissingElements, red)
+	restoreStrings(result.Recommendations, red)
+	result.Summary = red.Restore(result.Summary)
+
+	if t := result.Terms; t != nil {
+		for _, s := range []*string{&t.EmployerName, &t.Role, &t.StartDate, &t.Bonus, &t.WorkLocation, &t.GoverningLaw} {
+			*s = red.Restore(*s)
+		}
+		if t.NonCompete != nil {
+			t.NonCompete.Geography = red.Restore(t.NonCompete.Geography)
+		}
+	}
+}
+
+// restoreFindings restores the quote and description of each finding
+func restoreFindings(findings []models.Finding, red *redact.Result) {
+	if red == nil {
+		return
+	}
+	for i := range findings {
+		findings[i].Quote = red.Restore(findings[i].Quote)
+		findings[i].Description = red.Restore(findings[i].Description)
+	}
+}
+
+func restoreStrings(values []string, red *redact.Result) {
+	for i := range values {
+		values[i] = red.Restore(values[i])
+	}
+}
+
+// redactionReport summarizes what was redacted; it never includes the
+// redacted values
+func redactionReport(red *redact.Result) *models.RedactionReport {
+	if red == nil {
+		return &models.RedactionReport{Enabled: false, Counts: map[string]int{}}
+	}
+	report := &models.RedactionReport{Enabled: true, Counts: red.Counts()}
+	for _, e := range red.Entities {
+		report.Total += e.Count
+	}
+	return report
+}
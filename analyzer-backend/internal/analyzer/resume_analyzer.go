@@ -1,14 +1,16 @@
 package analyzer
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"strings"
+	"time"
 
+	"ea-scanner/internal/cache"
 	"ea-scanner/internal/models"
-
-	"google.golang.org/genai"
 )
 
 const resumeSystemPrompt = `You are an expert ATS (Applicant Tracking System) resume analyzer. Analyze resumes for ATS optimization based on proven research findings.
@@ -113,138 +115,92 @@ Respond ONLY with valid JSON in this exact format:
   ]
 }`
 
+// resumeAnalysisSchema constrains and validates resume analysis output
+var resumeAnalysisSchema = SchemaFor(models.ResumeAnalysisResult{})
+
+// DefaultResumeModel is the Gemini model used for resume analysis when no
+// model is requested
+const DefaultResumeModel = "gemini-2.5-pro"
+
 // ResumeAnalyzer handles resume-specific analysis
-type ResumeAnalyzer struct{}
+type ResumeAnalyzer struct {
+	provider Provider
+	config   Config
+}
 
-// NewResumeAnalyzer creates a new ResumeAnalyzer
-func NewResumeAnalyzer() *ResumeAnalyzer {
-	return &ResumeAnalyzer{}
+// NewResumeAnalyzer creates a new ResumeAnalyzer backed by the given provider
+func NewResumeAnalyzer(provider Provider, config Config) *ResumeAnalyzer {
+	return &ResumeAnalyzer{provider: provider, config: config}
 }
 
-// AnalyzeResume processes the resume text using the client's Gemini API key
-func (a *ResumeAnalyzer) AnalyzeResume(ctx context.Context, apiKey, resumeText, model string) (*models.ResumeAnalysisResult, error) {
-	// Create client with user's API key
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  apiKey,
-		Backend: genai.BackendGeminiAPI,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
-	}
+// AnalyzeResume processes the resume text using the configured provider.
+// Only the Model and NoCache options apply to resumes.
+func (a *ResumeAnalyzer) AnalyzeResume(ctx context.Context, apiKey, resumeText string, opts Options) (*models.ResumeAnalysisResult, error) {
+	start := time.Now()
 
-	// Use provided model or default
-	if model == "" {
-		model = "gemini-2.5-pro"
+	// Use requested model or default
+	if err := a.config.CheckModel(opts.Model); err != nil {
+		return nil, err
+	}
+	model := cmp.Or(opts.Model, a.config.ResumeModel)
+
+	// Serve repeated resumes from the cache; results are never shared between
+	// callers
+	var cacheKey, cacheStatus string
+	if a.config.Cache != nil {
+		cacheKey = cache.Key("resume", a.provider.Name(), apiKey, model, resumeSystemPrompt, resumeText)
+		cacheStatus = cache.StatusBypass
+		if !opts.NoCache {
+			if cached, ok := cacheLookup[models.ResumeAnalysisResult](ctx, a.config, cacheKey); ok {
+				cached.CacheStatus = cache.StatusHit
+				cached.Usage = cachedUsage(cached.Usage, start)
+				return cached, nil
+			}
+			cacheStatus = cache.StatusMiss
+		}
 	}
 
 	// Prepare the combined prompt
 	fullPrompt := fmt.Sprintf("%s\n\nAnalyze this resume for ATS optimization:\n\n---\n%s\n---", resumeSystemPrompt, resumeText)
 
-	// Generate analysis
-	resp, err := client.Models.GenerateContent(ctx, model,
-		genai.Text(fullPrompt),
-		nil,
-	)
+	// Generate analysis, repairing invalid output as needed
+	emit(ctx, Event{Stage: StageLLMStarted, Message: fmt.Sprintf("Analyzing resume with %s", model)})
+	result, gen, err := generateStructured(ctx, a.provider, a.config.Retry, &GenerateRequest{
+		APIKey: apiKey,
+		Model:  model,
+		Prompt: fullPrompt,
+		Schema: resumeAnalysisSchema,
+	}, parseResumeAnalysisResponse)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate analysis: %w", err)
+		return nil, fmt.Errorf("failed to generate analysis after %d attempt(s): %w", gen.attempts, err)
 	}
+	result.Attempts = gen.attempts
+	result.Usage = a.config.usageFor(gen)
+	result.Usage.LatencyMS = time.Since(start).Milliseconds()
 
-	// Extract text response
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("empty response from Gemini")
-	}
-
-	responseText := resp.Text()
-
-	// Parse JSON response
-	result, err := parseResumeAnalysisResponse(responseText)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse analysis response: %w", err)
+	stored := false
+	if cacheKey != "" {
+		stored = cacheStore(ctx, a.config, cacheKey, result)
 	}
+	result.CacheStatus = storedStatus(cacheStatus, stored)
 
 	return result, nil
 }
 
-// parseResumeAnalysisResponse extracts JSON from the Gemini response
+// parseResumeAnalysisResponse validates the model output against the resume
+// schema and decodes it
 func parseResumeAnalysisResponse(response string) (*models.ResumeAnalysisResult, error) {
-	// Clean up response - find JSON content
-	response = strings.TrimSpace(response)
-
-	// Remove markdown code block if present
-	if strings.Contains(response, "```json") {
-		start := strings.Index(response, "```json")
-		end := strings.LastIndex(response, "```")
-		if start != -1 && end > start+7 {
-			response = strings.TrimSpace(response[start+7 : end])
-		}
-	} else if strings.Contains(response, "```") {
-		// Generic code block
-		start := strings.Index(response, "```")
-		end := strings.LastIndex(response, "```")
-		if end > start+3 {
-			response = strings.TrimSpace(response[start+3 : end])
-		}
-	}
-
-	// Find JSON object with proper brace matching
-	start := strings.Index(response, "{")
-	if start == -1 {
-		return nil, fmt.Errorf("no JSON object found in response")
-	}
-
-	// Find matching closing brace
-	braceCount := 0
-	end := -1
-	for i := start; i < len(response); i++ {
-		if response[i] == '{' {
-			braceCount++
-		} else if response[i] == '}' {
-			braceCount--
-			if braceCount == 0 {
-				end = i
-				break
-			}
-		}
-	}
+	data := []byte(strings.TrimSpace(response))
 
-	if end == -1 {
-		return nil, fmt.Errorf("no matching closing brace found")
+	if err := resumeAnalysisSchema.Validate(data); err != nil {
+		log.Printf("Resume analysis response failed validation: %v: %s", err, data[:min(len(data), 500)])
+		return nil, err
 	}
 
-	jsonStr := response[start : end+1]
-
 	var result models.ResumeAnalysisResult
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, fmt.Errorf("invalid JSON format: %w", err)
 	}
 
-	// Validate overall score
-	if result.OverallScore < 0 {
-		result.OverallScore = 0
-	} else if result.OverallScore > 100 {
-		result.OverallScore = 100
-	}
-
-	// Validate score category
-	validCategories := map[string]bool{
-		"TOP_1%": true, "TOP_5%": true, "TOP_14%": true,
-		"TOP_30%": true, "NEEDS_WORK": true,
-	}
-	if !validCategories[result.ScoreCategory] {
-		// Derive from score
-		switch {
-		case result.OverallScore >= 90:
-			result.ScoreCategory = "TOP_1%"
-		case result.OverallScore >= 80:
-			result.ScoreCategory = "TOP_5%"
-		case result.OverallScore >= 70:
-			result.ScoreCategory = "TOP_14%"
-		case result.OverallScore >= 50:
-			result.ScoreCategory = "TOP_30%"
-		default:
-			result.ScoreCategory = "NEEDS_WORK"
-		}
-	}
-
 	return &result, nil
 }
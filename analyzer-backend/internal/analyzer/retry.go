@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"time"
+	"unicode/utf8"
+)
+
+// RetryPolicy bounds how the analyzers recover from failed model calls
+type RetryPolicy struct {
+	MaxRepairs int           // Follow-up prompts asking the model to fix invalid output
+	MaxRetries int           // Retries after transient API errors (429/503)
+	BaseDelay  time.Duration // Initial backoff delay
+	MaxDelay   time.Duration // Upper bound for a single backoff delay
+}
+
+// DefaultRetryPolicy returns the default retry policy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRepairs: 2,
+		MaxRetries: 3,
+		BaseDelay:  time.Second,
+		MaxDelay:   30 * time.Second,
+	}
+}
+
+// backoff returns the delay before the given retry (0-based), using
+// exponential backoff with jitter
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseDelay << retry
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// Equal jitter: half fixed, half random
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// maxRepairPromptOutput caps how much of a broken response is echoed back
+const maxRepairPromptOutput = 20000
+
+// generation describes the provider calls made for one structured result
+type generation struct {
+	attempts int    // Provider calls made
+	model    string // Model that served the last call
+	usage    Usage  // Tokens summed over every call, including repairs
+}
+
+// generateStructured calls the provider until parse accepts its output.
+// Invalid output is sent back to the model with the parse error for repair,
+// and transient API errors are retried with backoff. It returns the parsed
+// result and a record of the provider calls made.
+func generateStructured[T any](ctx context.Context, provider Provider, policy RetryPolicy, req *GenerateRequest, parse func(string) (T, error)) (T, generation, error) {
+	var zero T
+	call := *req
+	gen := generation{model: req.Model}
+	repairs, retries := 0, 0
+
+	for {
+		gen.attempts++
+		resp, err := provider.GenerateJSON(ctx, &call)
+		if err != nil {
+			if !isTransient(err) || retries >= policy.MaxRetries {
+				return zero, gen, err
+			}
+			delay := policy.backoff(retries)
+			retries++
+			log.Printf("Transient provider error (retry %d/%d in %s): %v", retries, policy.MaxRetries, delay.Round(time.Millisecond), err)
+
+			select {
+			case <-ctx.Done():
+				return zero, gen, ctx.Err()
+			case <-time.After(delay):
+			}
+			continue
+		}
+
+		gen.usage.add(resp.Usage)
+		if resp.Model != "" {
+			gen.model = resp.Model
+		}
+
+		result, err := parse(resp.Text)
+		if err == nil {
+			return result, gen, nil
+		}
+		if repairs >= policy.MaxRepairs {
+			return zero, gen, err
+		}
+		repairs++
+		log.Printf("Invalid model output (repair %d/%d): %v", repairs, policy.MaxRepairs, err)
+
+		call.Prompt = repairPrompt(req.Prompt, resp.Text, err)
+	}
+}
+
+// repairPrompt asks the model to correct its previous output
+func repairPrompt(original, output string, parseErr error) string {
+	if len(output) > maxRepairPromptOutput {
+		cut := maxRepairPromptOutput
+		for cut > 0 && !utf8.RuneStart(output[cut]) {
+			cut--
+		}
+		output = output[:cut]
+	}
+	return fmt.Sprintf("%s\n\nYour previous response could not be used:\n%v\n\nPrevious response:\n%s\n\nReturn the corrected JSON object only, with every required field and no extra fields.", original, parseErr, output)
+}
+
+// isTransient reports whether a provider error is worth retrying
+func isTransient(err error) bool {
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) {
+		return false
+	}
+	switch providerErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
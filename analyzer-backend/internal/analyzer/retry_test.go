@@ -0,0 +1,202 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// parseOK accepts only the literal response "ok"
+func parseOK(text string) (string, error) {
+	if text != "ok" {
+		return "", fmt.Errorf("want ok, got %q", text)
+	}
+	return text, nil
+}
+
+func TestGenerateStructured(t *testing.T) {
+	policy := RetryPolicy{MaxRepairs: 2, MaxRetries: 2}
+	unavailable := &ProviderError{Provider: "fake", StatusCode: http.StatusServiceUnavailable, Message: "overloaded"}
+	rateLimited := &ProviderError{Provider: "fake", StatusCode: http.StatusTooManyRequests, Message: "slow down"}
+	badRequest := &ProviderError{Provider: "fake", StatusCode: http.StatusBadRequest, Message: "bad prompt"}
+	networkErr := errors.New("connection reset")
+
+	tests := []struct {
+		name         string
+		responses    []string
+		errors       []error
+		wantErr      error // nil for success; errAny for any error
+		wantAttempts int
+		wantRepairs  int // Calls whose prompt asked for a repair
+	}{
+		{
+			name:         "valid first time",
+			responses:    []string{"ok"},
+			wantAttempts: 1,
+		},
+		{
+			name:         "repaired once",
+			responses:    []string{"broken", "ok"},
+			wantAttempts: 2,
+			wantRepairs:  1,
+		},
+		{
+			name:         "repaired up to the limit",
+			responses:    []string{"broken", "still broken", "ok"},
+			wantAttempts: 3,
+			wantRepairs:  2,
+		},
+		{
+			name:         "repairs exhausted",
+			responses:    []string{"broken"},
+			wantErr:      errAny,
+			wantAttempts: 3,
+			wantRepairs:  2,
+		},
+		{
+			name:         "retries 429",
+			responses:    []string{"ok"},
+			errors:       []error{rateLimited},
+			wantAttempts: 2,
+		},
+		{
+			name:         "retries 503",
+			responses:    []string{"ok"},
+			errors:       []error{unavailable, unavailable},
+			wantAttempts: 3,
+		},
+		{
+			name:         "retries exhausted",
+			responses:    []string{"ok"},
+			errors:       []error{unavailable, rateLimited, unavailable},
+			wantErr:      unavailable,
+			wantAttempts: 3,
+		},
+		{
+			name:         "no retry on 400",
+			responses:    []string{"ok"},
+			errors:       []error{badRequest},
+			wantErr:      badRequest,
+			wantAttempts: 1,
+		},
+		{
+			name:         "no retry on other errors",
+			responses:    []string{"ok"},
+			errors:       []error{networkErr},
+			wantErr:      networkErr,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retry then repair",
+			responses:    []string{"broken", "ok"},
+			errors:       []error{unavailable},
+			wantAttempts: 3,
+			wantRepairs:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewFakeProvider(tt.responses...)
+			provider.Errors = tt.errors
+			provider.Usage = Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}
+
+			result, gen, err := generateStructured(context.Background(), provider, policy, &GenerateRequest{Model: "fake-model", Prompt: "analyze"}, parseOK)
+
+			switch {
+			case tt.wantErr == nil:
+				if err != nil {
+					t.Fatalf("generateStructured() error = %v", err)
+				}
+				if result != "ok" {
+					t.Errorf("result = %q, want ok", result)
+				}
+			case tt.wantErr == errAny:
+				if err == nil {
+					t.Fatal("generateStructured() succeeded, want error")
+				}
+			default:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("generateStructured() error = %v, want %v", err, tt.wantErr)
+				}
+			}
+
+			if gen.attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", gen.attempts, tt.wantAttempts)
+			}
+			if provider.Calls() != tt.wantAttempts {
+				t.Errorf("provider calls = %d, want %d", provider.Calls(), tt.wantAttempts)
+			}
+
+			repairs, successes := 0, 0
+			for i, req := range provider.Requests {
+				if strings.Contains(req.Prompt, "Your previous response could not be used") {
+					repairs++
+				}
+				if i >= len(tt.errors) || tt.errors[i] == nil {
+					successes++
+				}
+			}
+			if repairs != tt.wantRepairs {
+				t.Errorf("repair prompts = %d, want %d", repairs, tt.wantRepairs)
+			}
+
+			// Usage covers every answered call, including repairs
+			if want := successes * provider.Usage.TotalTokens; gen.usage.TotalTokens != want {
+				t.Errorf("usage total = %d, want %d", gen.usage.TotalTokens, want)
+			}
+		})
+	}
+}
+
+func TestGenerateStructuredRepairPrompt(t *testing.T) {
+	provider := NewFakeProvider("broken", "ok")
+
+	if _, _, err := generateStructured(context.Background(), provider, RetryPolicy{MaxRepairs: 1}, &GenerateRequest{Prompt: "analyze"}, parseOK); err != nil {
+		t.Fatalf("generateStructured() error = %v", err)
+	}
+
+	repair := provider.Requests[1].Prompt
+	for _, want := range []string{"analyze", `want ok, got "broken"`, "Previous response:\nbroken"} {
+		if !strings.Contains(repair, want) {
+			t.Errorf("repair prompt %q does not contain %q", repair, want)
+		}
+	}
+}
+
+func TestRepairPromptTruncatesOnRuneBoundary(t *testing.T) {
+	// The cap falls inside a three-byte rune
+	output := "x" + strings.Repeat("条", maxRepairPromptOutput/3+1)
+
+	prompt := repairPrompt("analyze", output, errors.New("invalid"))
+	if !utf8.ValidString(prompt) {
+		t.Error("repair prompt is not valid UTF-8")
+	}
+	if !strings.Contains(prompt, output[:maxRepairPromptOutput-2]) {
+		t.Error("repair prompt does not contain the output up to the cap")
+	}
+}
+
+func TestGenerateStructuredCanceledDuringBackoff(t *testing.T) {
+	provider := NewFakeProvider("ok")
+	provider.Errors = []error{&ProviderError{Provider: "fake", StatusCode: http.StatusTooManyRequests}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	_, gen, err := generateStructured(ctx, provider, policy, &GenerateRequest{}, parseOK)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if gen.attempts != 1 {
+		t.Errorf("attempts = %d, want 1", gen.attempts)
+	}
+}
+
+// errAny matches any error in table tests
+var errAny = errors.New("any error")
@@ -0,0 +1,243 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema used to constrain and validate model output
+type Schema struct {
+	Type                 string             `json:"type"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// SchemaFor derives a Schema from a struct value using its json tags.
+//
+// The `schema` struct tag refines the generated schema:
+//
+//	schema:"-"                     field is server-populated and excluded
+//	schema:"optional"              field may be omitted by the model
+//	schema:"enum=LOW|MEDIUM|HIGH"  string must be one of the listed values
+//	schema:"min=0,max=100"         numeric range (inclusive)
+func SchemaFor(v any) *Schema {
+	return schemaForType(reflect.TypeOf(v))
+}
+
+func schemaForType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		closed := false
+		s := &Schema{
+			Type:                 "object",
+			Properties:           map[string]*Schema{},
+			AdditionalProperties: &closed,
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			opts := parseSchemaTag(field.Tag.Get("schema"))
+			if _, skip := opts["-"]; skip {
+				continue
+			}
+
+			prop := schemaForType(field.Type)
+			if enum, ok := opts["enum"]; ok {
+				prop.Enum = strings.Split(enum, "|")
+			}
+			if lo, ok := opts["min"]; ok {
+				if f, err := strconv.ParseFloat(lo, 64); err == nil {
+					prop.Minimum = &f
+				}
+			}
+			if hi, ok := opts["max"]; ok {
+				if f, err := strconv.ParseFloat(hi, 64); err == nil {
+					prop.Maximum = &f
+				}
+			}
+
+			s.Properties[name] = prop
+			if _, optional := opts["optional"]; !optional && field.Type.Kind() != reflect.Pointer {
+				s.Required = append(s.Required, name)
+			}
+		}
+		sort.Strings(s.Required)
+		return s
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaForType(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+// parseSchemaTag splits a schema tag into key/value options
+func parseSchemaTag(tag string) map[string]string {
+	opts := map[string]string{}
+	if tag == "" {
+		return opts
+	}
+	for _, part := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		opts[key] = value
+	}
+	return opts
+}
+
+// Violation describes a single schema violation in model output
+type Violation struct {
+	Path    string `json:"path"`    // JSON path, e.g. "scam_indicators[0].severity"
+	Message string `json:"message"` // What is wrong
+}
+
+// ValidationError is returned when model output does not conform to the schema
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", v.Path, v.Message))
+	}
+	return "response does not match schema: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks that data is a single JSON value conforming to the schema.
+// It returns a *ValidationError listing every violation found.
+func (s *Schema) Validate(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return &ValidationError{Violations: []Violation{{Path: "$", Message: "invalid JSON: " + err.Error()}}}
+	}
+	if dec.More() {
+		return &ValidationError{Violations: []Violation{{Path: "$", Message: "unexpected data after JSON value"}}}
+	}
+
+	var violations []Violation
+	s.validate("$", value, &violations)
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func (s *Schema) validate(path string, value any, violations *[]Violation) {
+	fail := func(format string, args ...any) {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			fail("expected object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				fail("missing required field %q", name)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			prop, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					fail("unknown field %q", key)
+				}
+				continue
+			}
+			if obj[key] == nil && !slices.Contains(s.Required, key) {
+				// Optional fields may be explicitly null
+				continue
+			}
+			prop.validate(path+"."+key, obj[key], violations)
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			fail("expected array")
+			return
+		}
+		for i, item := range arr {
+			s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, violations)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("expected string")
+			return
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			fail("%q is not one of %s", str, strings.Join(s.Enum, ", "))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected boolean")
+		}
+	case "integer", "number":
+		num, ok := value.(json.Number)
+		if !ok {
+			fail("expected %s", s.Type)
+			return
+		}
+		f, err := num.Float64()
+		if err != nil {
+			fail("invalid number %s", num)
+			return
+		}
+		if s.Type == "integer" {
+			if _, err := num.Int64(); err != nil {
+				fail("expected integer, got %s", num)
+				return
+			}
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			fail("%s is below minimum %v", num, *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			fail("%s is above maximum %v", num, *s.Maximum)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"testing"
+
+	"ea-scanner/internal/models"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	suggestion := SchemaFor(models.ResumeSuggestion{})
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "complete",
+			data: `{"priority":"HIGH","category":"ActionVerbs","current":"Responsible for sales","suggested":"Grew sales","explanation":"Verbs show impact"}`,
+		},
+		{
+			name: "optional field omitted",
+			data: `{"priority":"LOW","category":"Structure","suggested":"Add a skills section","explanation":"ATS parse sections"}`,
+		},
+		{
+			name:    "required field missing",
+			data:    `{"priority":"LOW","category":"Structure","explanation":"ATS parse sections"}`,
+			wantErr: true,
+		},
+		{
+			name:    "value outside enum",
+			data:    `{"priority":"URGENT","category":"Structure","suggested":"Add a skills section","explanation":"ATS parse sections"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := suggestion.Validate([]byte(tt.data)); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
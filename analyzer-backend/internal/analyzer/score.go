@@ -0,0 +1,37 @@
+package analyzer
+
+import "ea-scanner/internal/models"
+
+// severityWeight is each severity's contribution to a computed risk score
+var severityWeight = map[string]int{
+	"LOW":      5,
+	"MEDIUM":   15,
+	"HIGH":     30,
+	"CRITICAL": 50,
+}
+
+// scoreFindings computes a 0-100 risk score from finding severities
+func scoreFindings(groups ...[]models.Finding) int {
+	score := 0
+	for _, findings := range groups {
+		for _, f := range findings {
+			score += severityWeight[f.Severity]
+		}
+	}
+	return min(score, 100)
+}
+
+// riskLevelForScore maps a risk score to its level, following the
+// guidelines given to the model in systemPrompt
+func riskLevelForScore(score int) string {
+	switch {
+	case score <= 25:
+		return "LOW"
+	case score <= 50:
+		return "MEDIUM"
+	case score <= 75:
+		return "HIGH"
+	default:
+		return "CRITICAL"
+	}
+}
@@ -0,0 +1,210 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"ea-scanner/internal/models"
+	"ea-scanner/internal/rules"
+)
+
+// Limits used by the term checks
+const (
+	maxProbationDays  = 180
+	maxNonCompeteDays = 365
+	maxHoursPerWeek   = 48
+)
+
+// unitDays approximates each duration unit in days
+var unitDays = map[string]float64{
+	"DAY":   1,
+	"WEEK":  7,
+	"MONTH": 30,
+	"YEAR":  365,
+}
+
+// worldwideTerms are non-compete geographies without a real limit
+var worldwideTerms = []string{"worldwide", "world", "global", "globally", "anywhere", "any country", "unlimited"}
+
+// checkTerms runs deterministic checks on the extracted terms and returns a
+// finding for each anomaly. Checks whose category was already reported are
+// skipped.
+func checkTerms(terms *models.ContractTerms, existing ...[]models.Finding) []models.Finding {
+	if terms == nil {
+		return nil
+	}
+
+	var findings []models.Finding
+	add := func(category, severity, description string) {
+		for _, group := range existing {
+			for _, f := range group {
+				if strings.EqualFold(f.Category, category) {
+					return
+				}
+			}
+		}
+		for _, f := range findings {
+			if f.Category == category {
+				return
+			}
+		}
+		findings = append(findings, models.Finding{
+			Category:    category,
+			Severity:    severity,
+			Description: description,
+			Source:      SourceCheck,
+		})
+	}
+
+	notice, hasNotice := durationDays(terms.NoticePeriod)
+	probation, hasProbation := durationDays(terms.ProbationPeriod)
+
+	if hasNotice && hasProbation && notice > probation {
+		add("Notice Period", "MEDIUM", fmt.Sprintf("The notice period (%s) is longer than the probation period (%s), which is unusual and can make it hard to leave early.",
+			formatDuration(terms.NoticePeriod), formatDuration(terms.ProbationPeriod)))
+	}
+	if hasProbation && probation > maxProbationDays {
+		add("Probation", "MEDIUM", fmt.Sprintf("The probation period (%s) is longer than six months.", formatDuration(terms.ProbationPeriod)))
+	}
+
+	if nc := terms.NonCompete; nc != nil {
+		if days, ok := durationDays(nc.Duration); ok && days > maxNonCompeteDays {
+			add("Non-Compete", "HIGH", fmt.Sprintf("The non-compete lasts %s after employment, longer than one year.", formatDuration(nc.Duration)))
+		}
+		if isWorldwide(nc.Geography) {
+			add("Non-Compete", "MEDIUM", fmt.Sprintf("The non-compete has no meaningful geographic limit (%q).", nc.Geography))
+		}
+	}
+
+	if terms.HoursPerWeek != nil && *terms.HoursPerWeek > maxHoursPerWeek {
+		add("Working Hours", "MEDIUM", fmt.Sprintf("Contracted hours (%g per week) exceed %d hours per week.", *terms.HoursPerWeek, maxHoursPerWeek))
+	}
+
+	return findings
+}
+
+// checkJurisdictionTerms applies the pack's term checks and returns a finding
+// for each limit the extracted terms exceed. Checks whose rule the model
+// already cited are skipped.
+func checkJurisdictionTerms(terms *models.ContractTerms, pack *rules.Pack, existing ...[]models.Finding) []models.Finding {
+	if terms == nil || pack == nil {
+		return nil
+	}
+
+	var findings []models.Finding
+	for _, check := range pack.TermChecks {
+		value, ok := termValue(terms, check.Term)
+		if !ok || !outOfRange(value, check.Min, check.Max) || citedRule(check.ID, existing...) {
+			continue
+		}
+		findings = append(findings, models.Finding{
+			Category:     check.Category,
+			Severity:     check.Severity,
+			Description:  check.Explanation,
+			Source:       SourceCheck,
+			RuleID:       check.ID,
+			Jurisdiction: pack.Jurisdiction,
+		})
+	}
+	return findings
+}
+
+// termValue returns the value of a term in the units used by term checks
+func termValue(terms *models.ContractTerms, term string) (float64, bool) {
+	switch term {
+	case rules.TermNoticePeriod:
+		return durationDays(terms.NoticePeriod)
+	case rules.TermProbationPeriod:
+		return durationDays(terms.ProbationPeriod)
+	case rules.TermNonCompete:
+		if terms.NonCompete == nil {
+			return 0, false
+		}
+		if days, ok := durationDays(terms.NonCompete.Duration); ok {
+			return days, true
+		}
+		return 1, true
+	case rules.TermHoursPerWeek:
+		if terms.HoursPerWeek == nil {
+			return 0, false
+		}
+		return *terms.HoursPerWeek, true
+	}
+	return 0, false
+}
+
+func outOfRange(value float64, lo, hi *float64) bool {
+	return (lo != nil && value < *lo) || (hi != nil && value > *hi)
+}
+
+func citedRule(id string, groups ...[]models.Finding) bool {
+	for _, group := range groups {
+		for _, f := range group {
+			if f.RuleID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// durationDays converts a duration to approximate days
+func durationDays(d *models.Duration) (float64, bool) {
+	if d == nil {
+		return 0, false
+	}
+	days, ok := unitDays[d.Unit]
+	return d.Value * days, ok
+}
+
+// formatDuration renders a duration as e.g. "3 months"
+func formatDuration(d *models.Duration) string {
+	unit := strings.ToLower(d.Unit)
+	if d.Value != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%g %s", d.Value, unit)
+}
+
+func isWorldwide(geography string) bool {
+	g := strings.ToLower(geography)
+	for _, term := range worldwideTerms {
+		if g == term || strings.HasPrefix(g, term+" ") || strings.Contains(g, " "+term) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeTerms fills terms missing from merged with those from terms; the first
+// part that states a term wins
+func mergeTerms(merged, terms *models.ContractTerms) *models.ContractTerms {
+	if terms == nil {
+		return merged
+	}
+	if merged == nil {
+		copied := *terms
+		return &copied
+	}
+
+	fill(&merged.EmployerName, terms.EmployerName)
+	fill(&merged.Role, terms.Role)
+	fill(&merged.StartDate, terms.StartDate)
+	fill(&merged.BaseSalary, terms.BaseSalary)
+	fill(&merged.Bonus, terms.Bonus)
+	fill(&merged.NoticePeriod, terms.NoticePeriod)
+	fill(&merged.ProbationPeriod, terms.ProbationPeriod)
+	fill(&merged.NonCompete, terms.NonCompete)
+	fill(&merged.WorkLocation, terms.WorkLocation)
+	fill(&merged.HoursPerWeek, terms.HoursPerWeek)
+	fill(&merged.GoverningLaw, terms.GoverningLaw)
+	return merged
+}
+
+// fill sets *dst to value if *dst is the zero value
+func fill[T comparable](dst *T, value T) {
+	var zero T
+	if *dst == zero {
+		*dst = value
+	}
+}
@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+
+	"ea-scanner/internal/models"
+	"ea-scanner/internal/rules"
+)
+
+// categories returns the categories of findings, in order
+func categories(findings []models.Finding) []string {
+	var cats []string
+	for _, f := range findings {
+		cats = append(cats, f.Category)
+	}
+	return cats
+}
+
+func TestCheckTerms(t *testing.T) {
+	months := func(n float64) *models.Duration { return &models.Duration{Value: n, Unit: "MONTH"} }
+	hours := func(n float64) *float64 { return &n }
+
+	tests := []struct {
+		name     string
+		terms    *models.ContractTerms
+		existing []models.Finding
+		want     []string
+	}{
+		{
+			name:  "no terms",
+			terms: nil,
+		},
+		{
+			name:  "usual terms",
+			terms: &models.ContractTerms{NoticePeriod: months(1), ProbationPeriod: months(3), HoursPerWeek: hours(40)},
+		},
+		{
+			name:  "notice longer than probation",
+			terms: &models.ContractTerms{NoticePeriod: months(3), ProbationPeriod: &models.Duration{Value: 6, Unit: "WEEK"}},
+			want:  []string{"Notice Period"},
+		},
+		{
+			name:  "long probation and hours",
+			terms: &models.ContractTerms{ProbationPeriod: &models.Duration{Value: 1, Unit: "YEAR"}, HoursPerWeek: hours(60)},
+			want:  []string{"Probation", "Working Hours"},
+		},
+		{
+			name:  "long worldwide non-compete reported once",
+			terms: &models.ContractTerms{NonCompete: &models.NonCompete{Duration: months(24), Geography: "Worldwide"}},
+			want:  []string{"Non-Compete"},
+		},
+		{
+			name:     "category already reported",
+			terms:    &models.ContractTerms{NonCompete: &models.NonCompete{Geography: "anywhere in the world"}},
+			existing: []models.Finding{{Category: "non-compete"}},
+		},
+		{
+			name:  "unknown unit",
+			terms: &models.ContractTerms{ProbationPeriod: &models.Duration{Value: 900, Unit: "FORTNIGHT"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := categories(checkTerms(tt.terms, tt.existing)); !slices.Equal(got, tt.want) {
+				t.Errorf("checkTerms() categories = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeTerms(t *testing.T) {
+	first := &models.ContractTerms{Role: "Analyst", NoticePeriod: &models.Duration{Value: 1, Unit: "MONTH"}}
+	second := &models.ContractTerms{Role: "Engineer", EmployerName: "Acme Ltd", NoticePeriod: &models.Duration{Value: 3, Unit: "MONTH"}}
+
+	merged := mergeTerms(mergeTerms(nil, first), second)
+	if merged.Role != "Analyst" || merged.EmployerName != "Acme Ltd" || merged.NoticePeriod.Value != 1 {
+		t.Errorf("mergeTerms() = %+v, want the first part's role and notice with the second part's employer", merged)
+	}
+	if first.EmployerName != "" {
+		t.Error("mergeTerms() modified its first input")
+	}
+}
+
+func TestCheckJurisdictionTerms(t *testing.T) {
+	pack, err := rules.Jurisdiction("IN")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		terms    *models.ContractTerms
+		existing []models.Finding
+		want     []string // Rule IDs
+	}{
+		{
+			name:  "within limits",
+			terms: &models.ContractTerms{NoticePeriod: &models.Duration{Value: 2, Unit: "MONTH"}},
+		},
+		{
+			name:  "non-compete without duration and long notice",
+			terms: &models.ContractTerms{NonCompete: &models.NonCompete{Geography: "Mumbai"}, NoticePeriod: &models.Duration{Value: 6, Unit: "MONTH"}},
+			want:  []string{"IN-NONCOMPETE", "IN-NOTICE"},
+		},
+		{
+			name:     "rule already cited",
+			terms:    &models.ContractTerms{NoticePeriod: &models.Duration{Value: 6, Unit: "MONTH"}},
+			existing: []models.Finding{{Category: "Notice", RuleID: "IN-NOTICE"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range checkJurisdictionTerms(tt.terms, pack, tt.existing) {
+				got = append(got, f.RuleID)
+				if f.Jurisdiction != "IN" {
+					t.Errorf("finding %s jurisdiction = %q, want IN", f.RuleID, f.Jurisdiction)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("checkJurisdictionTerms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"ea-scanner/internal/models"
+)
+
+// maxChangeQuote is the longest deleted text quoted in a description
+const maxChangeQuote = 160
+
+// checkTrackedChanges flags insertions and deletions left unresolved in the
+// document. The text is analyzed as if they were accepted, so deleted text is
+// never seen by the model; the finding quotes it instead. Checks whose
+// category was already reported are skipped.
+func checkTrackedChanges(changes []models.TrackedChange, existing ...[]models.Finding) []models.Finding {
+	if len(changes) == 0 {
+		return nil
+	}
+	for _, group := range existing {
+		for _, f := range group {
+			if strings.EqualFold(f.Category, "Unresolved Tracked Changes") {
+				return nil
+			}
+		}
+	}
+
+	var insertions, deletions int
+	var authors, deleted []string
+	for _, c := range changes {
+		if c.Type == models.ChangeDeletion {
+			deletions++
+			deleted = append(deleted, strings.Join(strings.Fields(c.Text), " "))
+		} else {
+			insertions++
+		}
+		if c.Author != "" && !containsFold(authors, c.Author) {
+			authors = append(authors, c.Author)
+		}
+	}
+
+	description := fmt.Sprintf("The document contains %d tracked insertion(s) and %d deletion(s) that have not been accepted or rejected", insertions, deletions)
+	if len(authors) > 0 {
+		description += " by " + strings.Join(authors, ", ")
+	}
+	description += ". Changes can be hidden when the document is viewed without markup, so review every change before signing."
+	if len(deleted) > 0 {
+		quote := strings.Join(deleted, " / ")
+		if len(quote) > maxChangeQuote {
+			quote = strings.ToValidUTF8(quote[:maxChangeQuote], "") + "..."
+		}
+		description += fmt.Sprintf(" Deleted text: %q.", quote)
+	}
+
+	return []models.Finding{{
+		Category:    "Unresolved Tracked Changes",
+		Severity:    "MEDIUM",
+		Description: description,
+		Source:      SourceCheck,
+	}}
+}
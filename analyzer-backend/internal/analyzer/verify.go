@@ -0,0 +1,264 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"ea-scanner/internal/models"
+)
+
+// minQuoteSimilarity is the word-level similarity a fuzzy match must reach
+const minQuoteSimilarity = 0.8
+
+// severityOrder lists severities from least to most severe
+var severityOrder = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// verifyFindings locates each finding's quote in the document text and fills
+// in its offsets and page. Findings whose quote cannot be found are
+// downgraded one severity level, or dropped when dropUnverified is set.
+func verifyFindings(findings []models.Finding, text string, dropUnverified bool) []models.Finding {
+	if len(findings) == 0 {
+		return findings
+	}
+
+	index := newTextIndex(text)
+	verified := make([]models.Finding, 0, len(findings))
+
+	for _, f := range findings {
+		if strings.TrimSpace(f.Quote) == "" {
+			// Nothing to check; keep the finding as-is
+			verified = append(verified, f)
+			continue
+		}
+
+		start, end, ok := index.find(f.Quote)
+		if !ok {
+			if dropUnverified {
+				continue
+			}
+			f.Verified = false
+			f.Severity = downgradeSeverity(f.Severity)
+			verified = append(verified, f)
+			continue
+		}
+
+		f.Verified = true
+		f.StartOffset = utf8.RuneCountInString(text[:start])
+		f.EndOffset = f.StartOffset + utf8.RuneCountInString(text[start:end])
+		f.Page = pageAt(text, start)
+		verified = append(verified, f)
+	}
+
+	return verified
+}
+
+// downgradeSeverity lowers a severity by one level
+func downgradeSeverity(severity string) string {
+	for i, s := range severityOrder {
+		if s == severity && i > 0 {
+			return severityOrder[i-1]
+		}
+	}
+	return severity
+}
+
+// pageAt returns the 1-based page containing byte offset pos, based on form
+// feed page separators. It returns 0 when the text has no page breaks.
+func pageAt(text string, pos int) int {
+	if !strings.Contains(text, "\f") {
+		return 0
+	}
+	return strings.Count(text[:pos], "\f") + 1
+}
+
+// textIndex is a match-friendly view of a document that maps back to byte
+// offsets in the original text
+type textIndex struct {
+	norm   string // Lowercased text with whitespace collapsed and punctuation unified
+	starts []int  // Original start byte offset of each normalized byte
+	ends   []int  // Original end byte offset of each normalized byte
+	words  []span // Word spans in norm
+}
+
+type span struct {
+	start, end int
+}
+
+func newTextIndex(text string) *textIndex {
+	idx := &textIndex{}
+	var b strings.Builder
+	lastSpace := true
+
+	for i, r := range text {
+		size := utf8.RuneLen(r)
+		if size < 0 {
+			size = 1
+		}
+		if unicode.IsSpace(r) {
+			if lastSpace {
+				continue
+			}
+			r = ' '
+			lastSpace = true
+		} else {
+			r = foldRune(r)
+			lastSpace = false
+		}
+
+		before := b.Len()
+		b.WriteRune(r)
+		for j := before; j < b.Len(); j++ {
+			idx.starts = append(idx.starts, i)
+			idx.ends = append(idx.ends, i+size)
+		}
+	}
+
+	idx.norm = b.String()
+	idx.words = wordSpans(idx.norm)
+	return idx
+}
+
+// find returns the byte range of quote in the original text
+func (idx *textIndex) find(quote string) (int, int, bool) {
+	q := normalizeForMatch(quote)
+	if q == "" {
+		return 0, 0, false
+	}
+
+	// Exact match on normalized text
+	if pos := strings.Index(idx.norm, q); pos >= 0 {
+		return idx.starts[pos], idx.ends[pos+len(q)-1], true
+	}
+
+	// Fuzzy word-level match
+	first, last, ok := idx.fuzzyFind(q)
+	if !ok {
+		return 0, 0, false
+	}
+	return idx.starts[idx.words[first].start], idx.ends[idx.words[last].end-1], true
+}
+
+// fuzzyFind finds the run of document words most similar to the quote. It
+// returns the indexes of the first and last matching words.
+func (idx *textIndex) fuzzyFind(q string) (int, int, bool) {
+	quoteWords := strings.Fields(q)
+	n := len(quoteWords)
+	if n < 3 || n > len(idx.words) {
+		return 0, 0, false
+	}
+	for i, w := range quoteWords {
+		quoteWords[i] = trimPunct(w)
+	}
+
+	docWords := make([]string, len(idx.words))
+	for i, w := range idx.words {
+		docWords[i] = trimPunct(idx.norm[w.start:w.end])
+	}
+
+	// Anchor candidate windows on the first few quote words
+	anchors := map[string][]int{}
+	for i := 0; i < n && i < 5; i++ {
+		anchors[quoteWords[i]] = append(anchors[quoteWords[i]], i)
+	}
+
+	bestScore, bestStart := 0.0, -1
+	tried := map[int]bool{}
+	for i, w := range docWords {
+		for _, offset := range anchors[w] {
+			start := i - offset
+			if start < 0 || start+n > len(docWords) || tried[start] {
+				continue
+			}
+			tried[start] = true
+
+			score := 1 - float64(wordDistance(quoteWords, docWords[start:start+n]))/float64(n)
+			if score > bestScore {
+				bestScore, bestStart = score, start
+			}
+		}
+	}
+
+	if bestStart < 0 || bestScore < minQuoteSimilarity {
+		return 0, 0, false
+	}
+
+	// A changed number or amount is a misquote, not a paraphrase
+	window := docWords[bestStart : bestStart+n]
+	for _, w := range quoteWords {
+		if strings.ContainsAny(w, "0123456789") && !slices.Contains(window, w) {
+			return 0, 0, false
+		}
+	}
+
+	return bestStart, bestStart + n - 1, true
+}
+
+// trimPunct strips leading and trailing punctuation from a word
+func trimPunct(w string) string {
+	return strings.TrimFunc(w, unicode.IsPunct)
+}
+
+// wordDistance is the Levenshtein distance between two word sequences
+func wordDistance(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// wordSpans returns the spans of space-separated words in s
+func wordSpans(s string) []span {
+	var spans []span
+	start := -1
+	for i := 0; i < len(s); i++ {
+		if s[i] == ' ' {
+			if start >= 0 {
+				spans = append(spans, span{start, i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, span{start, len(s)})
+	}
+	return spans
+}
+
+// normalizeForMatch applies the same folding as textIndex to a standalone string
+func normalizeForMatch(s string) string {
+	fields := strings.Fields(s)
+	for i, f := range fields {
+		fields[i] = strings.Map(foldRune, f)
+	}
+	return strings.Join(fields, " ")
+}
+
+// foldRune lowercases a rune and maps typographic variants to ASCII
+func foldRune(r rune) rune {
+	switch r {
+	case '‘', '’', '‚', '′':
+		return '\''
+	case '“', '”', '„', '″':
+		return '"'
+	case '‐', '‑', '‒', '–', '—', '−':
+		return '-'
+	}
+	return unicode.ToLower(r)
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"testing"
+
+	"ea-scanner/internal/models"
+)
+
+func TestVerifyFindings(t *testing.T) {
+	text := "Offer Letter\fThe Employee’s salary is ₹50,000 per month.\nThe Employee must pay a training fee of ₹5,000."
+
+	tests := []struct {
+		name           string
+		quote          string
+		dropUnverified bool
+		want           models.Finding // Offsets in runes
+		wantDropped    bool
+	}{
+		{
+			name:  "straight apostrophe and multibyte text",
+			quote: "The Employee's salary is ₹50,000",
+			want:  models.Finding{Quote: "The Employee's salary is ₹50,000", Severity: "HIGH", StartOffset: 13, EndOffset: 45, Page: 2, Verified: true},
+		},
+		{
+			name:  "whitespace and case differences",
+			quote: "MUST pay  a\ntraining fee",
+			want:  models.Finding{Quote: "MUST pay  a\ntraining fee", Severity: "HIGH", StartOffset: 70, EndOffset: 93, Page: 2, Verified: true},
+		},
+		{
+			name:  "paraphrased word",
+			quote: "The Employee must pay the training fee of ₹5,000.",
+			want:  models.Finding{Quote: "The Employee must pay the training fee of ₹5,000.", Severity: "HIGH", StartOffset: 57, EndOffset: 104, Page: 2, Verified: true},
+		},
+		{
+			name:  "changed amount is downgraded",
+			quote: "The Employee must pay a training fee of ₹9,000.",
+			want:  models.Finding{Quote: "The Employee must pay a training fee of ₹9,000.", Severity: "MEDIUM"},
+		},
+		{
+			name:           "unverified finding dropped",
+			quote:          "The Employee must buy a laptop from our vendor.",
+			dropUnverified: true,
+			wantDropped:    true,
+		},
+		{
+			name: "no quote",
+			want: models.Finding{Severity: "HIGH"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := verifyFindings([]models.Finding{{Quote: tt.quote, Severity: "HIGH"}}, text, tt.dropUnverified)
+			if tt.wantDropped {
+				if len(got) != 0 {
+					t.Errorf("verifyFindings() = %+v, want the finding dropped", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("verifyFindings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageAt(t *testing.T) {
+	tests := []struct {
+		text string
+		pos  int
+		want int
+	}{
+		{"one page", 3, 0},
+		{"first\fsecond\fthird", 0, 1},
+		{"first\fsecond\fthird", 6, 2},
+		{"first\fsecond\fthird", 13, 3},
+	}
+	for _, tt := range tests {
+		if got := pageAt(tt.text, tt.pos); got != tt.want {
+			t.Errorf("pageAt(%q, %d) = %d, want %d", tt.text, tt.pos, got, tt.want)
+		}
+	}
+}
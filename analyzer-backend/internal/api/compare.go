@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"ea-scanner/internal/diff"
+	"ea-scanner/internal/models"
+	"ea-scanner/internal/parser"
+)
+
+// handleCompare analyzes two versions of an agreement and reports what changed
+func (h *Handler) handleCompare(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	w.Header().Set("Content-Type", "application/json")
+
+	// Parse request body
+	var req models.CompareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	// Validate and parse both versions
+	versions := []*models.AnalyzeRequest{
+		{APIKey: req.APIKey, Document: req.Original.Document, Filename: req.Original.Filename, Model: req.Model, Jurisdiction: req.Jurisdiction, SkipRedaction: req.SkipRedaction, NoCache: req.NoCache},
+		{APIKey: req.APIKey, Document: req.Revised.Document, Filename: req.Revised.Filename, Model: req.Model, Jurisdiction: req.Jurisdiction, SkipRedaction: req.SkipRedaction, NoCache: req.NoCache},
+	}
+	names := []string{"Original", "Revised"}
+	docs := make([]*parser.Document, len(versions))
+	for i, v := range versions {
+		doc, reqErr := h.prepareAnalyze(v)
+		if reqErr != nil {
+			sendError(w, reqErr.status, names[i]+": "+reqErr.message, reqErr.details)
+			return
+		}
+		docs[i] = doc
+	}
+
+	// Analyze both versions concurrently
+	results := make([]*models.AnalysisResult, len(versions))
+	errs := make([]error, len(versions))
+	var wg sync.WaitGroup
+	for i, v := range versions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = h.runAnalyze(r.Context(), v, docs[i])
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			sendError(w, analysisErrorStatus(err), names[i]+" analysis failed", err.Error())
+			return
+		}
+	}
+
+	result := compareResults(results[0], results[1], docs[0].Clauses, docs[1].Clauses)
+	log.Printf("Comparison complete: %d clause change(s), risk score %+d", len(result.Changes), result.RiskScoreDelta)
+
+	// Send response
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// compareResults builds the comparison of two analyzed versions
+func compareResults(original, revised *models.AnalysisResult, originalClauses, revisedClauses []*models.Clause) *models.CompareResult {
+	originalFindings := append(append([]models.Finding{}, original.ScamIndicators...), original.RiskyClauses...)
+	revisedFindings := append(append([]models.Finding{}, revised.ScamIndicators...), revised.RiskyClauses...)
+	changes, clauseIDs := diff.Compare(originalClauses, revisedClauses)
+	fixed, introduced, severityChanges := diff.Findings(originalFindings, revisedFindings, clauseIDs)
+
+	return &models.CompareResult{
+		Original:           original,
+		Revised:            revised,
+		Changes:            changes,
+		FixedFindings:      fixed,
+		IntroducedFindings: introduced,
+		SeverityChanges:    severityChanges,
+		RiskScoreDelta:     revised.RiskScore - original.RiskScore,
+	}
+}
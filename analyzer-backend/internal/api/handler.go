@@ -1,36 +1,63 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 
 	"ea-scanner/internal/analyzer"
+	"ea-scanner/internal/cache"
+	"ea-scanner/internal/jobs"
+	"ea-scanner/internal/metrics"
 	"ea-scanner/internal/models"
 	"ea-scanner/internal/parser"
+	"ea-scanner/internal/rules"
 )
 
 // Handler holds the API handlers
 type Handler struct {
+	provider       analyzer.Provider
+	config         analyzer.Config
 	analyzer       *analyzer.Analyzer
 	resumeAnalyzer *analyzer.ResumeAnalyzer
+	jobs           *jobs.Manager
+	metrics        *metrics.Recorder
 }
 
-// NewHandler creates a new Handler
-func NewHandler() *Handler {
+// NewHandler creates a new Handler using the given LLM provider and job manager
+func NewHandler(provider analyzer.Provider, config analyzer.Config, jobManager *jobs.Manager) *Handler {
 	return &Handler{
-		analyzer:       analyzer.New(),
-		resumeAnalyzer: analyzer.NewResumeAnalyzer(),
+		provider:       provider,
+		config:         config,
+		analyzer:       analyzer.New(provider, config),
+		resumeAnalyzer: analyzer.NewResumeAnalyzer(provider, config),
+		jobs:           jobManager,
+		metrics:        metrics.NewRecorder(),
 	}
 }
 
 // RegisterRoutes sets up the HTTP routes
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /health", h.handleHealth)
+	mux.HandleFunc("GET /api/metrics", h.handleMetrics)
+	mux.HandleFunc("GET /api/models", h.handleModels)
 	mux.HandleFunc("POST /api/analyze", h.handleAnalyze)
 	mux.HandleFunc("OPTIONS /api/analyze", h.handleCORS)
 	mux.HandleFunc("POST /api/resume/analyze", h.handleResumeAnalyze)
 	mux.HandleFunc("OPTIONS /api/resume/analyze", h.handleCORS)
+	mux.HandleFunc("POST /api/analyze/stream", h.handleAnalyzeStream)
+	mux.HandleFunc("OPTIONS /api/analyze/stream", h.handleCORS)
+	mux.HandleFunc("POST /api/analyze/compare", h.handleCompare)
+	mux.HandleFunc("OPTIONS /api/analyze/compare", h.handleCORS)
+	mux.HandleFunc("POST /api/resume/analyze/stream", h.handleResumeAnalyzeStream)
+	mux.HandleFunc("OPTIONS /api/resume/analyze/stream", h.handleCORS)
+	mux.HandleFunc("POST /api/jobs", h.handleCreateJob)
+	mux.HandleFunc("OPTIONS /api/jobs", h.handleCORS)
+	mux.HandleFunc("GET /api/jobs/{id}", h.handleGetJob)
+	mux.HandleFunc("DELETE /api/jobs/{id}", h.handleCancelJob)
+	mux.HandleFunc("OPTIONS /api/jobs/{id}", h.handleCORS)
 }
 
 // handleHealth returns server health status
@@ -43,6 +70,27 @@ func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleMetrics returns usage and cost totals since the server started
+func (h *Handler) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.metrics.Snapshot())
+}
+
+// handleModels lists the models clients may request
+func (h *Handler) handleModels(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ModelsResponse{
+		Provider: h.provider.Name(),
+		Defaults: map[string]string{
+			models.JobTypeAnalyze:       h.config.Model,
+			models.JobTypeResumeAnalyze: h.config.ResumeModel,
+		},
+		Models: h.config.Models(),
+	})
+}
+
 // handleCORS handles preflight requests
 func (h *Handler) handleCORS(w http.ResponseWriter, r *http.Request) {
 	setCORSHeaders(w)
@@ -61,47 +109,20 @@ func (h *Handler) handleAnalyze(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate required fields
-	if req.APIKey == "" {
-		sendError(w, http.StatusBadRequest, "API key is required", "")
-		return
-	}
-	if req.Document == "" {
-		sendError(w, http.StatusBadRequest, "Document is required", "")
-		return
-	}
-	if req.Filename == "" {
-		req.Filename = "document.txt"
-	}
-
-	// Parse document
-	text, err := parser.ParseDocument(req.Document, req.Filename)
-	if err != nil {
-		sendError(w, http.StatusBadRequest, "Failed to parse document", err.Error())
-		return
-	}
-
-	// Normalize text
-	text = parser.NormalizeText(text)
-
-	if len(text) < 50 {
-		sendError(w, http.StatusBadRequest, "Document too short", "Document must contain at least 50 characters of text")
+	doc, reqErr := h.prepareAnalyze(&req)
+	if reqErr != nil {
+		sendError(w, reqErr.status, reqErr.message, reqErr.details)
 		return
 	}
 
-	// Analyze with Gemini
-	log.Printf("Analyzing document: %s (%d chars)", req.Filename, len(text))
-
-	result, err := h.analyzer.Analyze(r.Context(), req.APIKey, text)
+	result, err := h.runAnalyze(r.Context(), &req, doc)
 	if err != nil {
-		log.Printf("Analysis error: %v", err)
-		sendError(w, http.StatusInternalServerError, "Analysis failed", err.Error())
+		sendError(w, analysisErrorStatus(err), "Analysis failed", err.Error())
 		return
 	}
 
-	log.Printf("Analysis complete: Risk Score %d (%s)", result.RiskScore, result.RiskLevel)
-
 	// Send response
+	setCacheStatus(w, result.CacheStatus)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(result)
 }
@@ -118,56 +139,211 @@ func (h *Handler) handleResumeAnalyze(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate required fields
-	if req.APIKey == "" {
-		sendError(w, http.StatusBadRequest, "API key is required", "")
+	doc, reqErr := h.prepareResume(&req)
+	if reqErr != nil {
+		sendError(w, reqErr.status, reqErr.message, reqErr.details)
 		return
 	}
-	if req.Document == "" {
-		sendError(w, http.StatusBadRequest, "Resume document is required", "")
+
+	result, err := h.runResume(r.Context(), &req, doc)
+	if err != nil {
+		sendError(w, analysisErrorStatus(err), "Resume analysis failed", err.Error())
 		return
 	}
+
+	// Send response
+	setCacheStatus(w, result.CacheStatus)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// requestError is a client-facing error with its HTTP status
+type requestError struct {
+	status  int
+	message string
+	details string
+}
+
+// prepareAnalyze validates an analysis request and returns the parsed document
+// with normalized text
+func (h *Handler) prepareAnalyze(req *models.AnalyzeRequest) (*parser.Document, *requestError) {
+	// Validate required fields
+	if req.APIKey == "" && h.provider.RequiresAPIKey() {
+		return nil, &requestError{http.StatusBadRequest, "API key is required", ""}
+	}
+	if req.Document == "" {
+		return nil, &requestError{http.StatusBadRequest, "Document is required", ""}
+	}
+	if req.Filename == "" {
+		req.Filename = "document.txt"
+	}
+	if err := h.config.CheckModel(req.Model); err != nil {
+		return nil, &requestError{http.StatusBadRequest, "Unsupported model", err.Error()}
+	}
+	if req.Jurisdiction != "" {
+		if _, err := rules.Jurisdiction(req.Jurisdiction); err != nil {
+			return nil, &requestError{http.StatusBadRequest, "Unsupported jurisdiction", err.Error()}
+		}
+	}
+
+	// Parse document
+	doc, err := parser.ParseDocument(req.Document, req.Filename)
+	if err != nil {
+		return nil, &requestError{parseErrorStatus(err), "Failed to parse document", err.Error()}
+	}
+
+	// Normalize text, split it into clauses and collect its contacts
+	doc.Normalize()
+	doc.Clauses = parser.Segment(doc.Paragraphs)
+	doc.Contacts = parser.ExtractContacts(doc.Text, doc.Links)
+
+	if len(doc.Text) < 50 {
+		return nil, &requestError{http.StatusBadRequest, "Document too short", "Document must contain at least 50 characters of text"}
+	}
+
+	return doc, nil
+}
+
+// prepareResume validates a resume analysis request and returns the parsed
+// resume with normalized text
+func (h *Handler) prepareResume(req *models.ResumeAnalyzeRequest) (*parser.Document, *requestError) {
+	// Validate required fields
+	if req.APIKey == "" && h.provider.RequiresAPIKey() {
+		return nil, &requestError{http.StatusBadRequest, "API key is required", ""}
+	}
+	if req.Document == "" {
+		return nil, &requestError{http.StatusBadRequest, "Resume document is required", ""}
+	}
 	if req.Filename == "" {
 		req.Filename = "resume.txt"
 	}
+	if err := h.config.CheckModel(req.Model); err != nil {
+		return nil, &requestError{http.StatusBadRequest, "Unsupported model", err.Error()}
+	}
 
 	// Parse document
-	text, err := parser.ParseDocument(req.Document, req.Filename)
+	doc, err := parser.ParseDocument(req.Document, req.Filename)
 	if err != nil {
-		sendError(w, http.StatusBadRequest, "Failed to parse resume", err.Error())
-		return
+		return nil, &requestError{parseErrorStatus(err), "Failed to parse resume", err.Error()}
 	}
 
 	// Normalize text
-	text = parser.NormalizeText(text)
+	doc.Normalize()
 
-	if len(text) < 100 {
-		sendError(w, http.StatusBadRequest, "Resume too short", "Resume must contain at least 100 characters of text")
-		return
+	if len(doc.Text) < 100 {
+		return nil, &requestError{http.StatusBadRequest, "Resume too short", "Resume must contain at least 100 characters of text"}
 	}
 
-	// Analyze resume with Gemini
-	log.Printf("Analyzing resume: %s (%d chars)", req.Filename, len(text))
+	return doc, nil
+}
+
+// runAnalyze analyzes a prepared agreement
+func (h *Handler) runAnalyze(ctx context.Context, req *models.AnalyzeRequest, doc *parser.Document) (*models.AnalysisResult, error) {
+	// Analyze with the configured provider
+	log.Printf("Analyzing document: %s (%s, %d chars)", req.Filename, doc.Format, len(doc.Text))
 
-	result, err := h.resumeAnalyzer.AnalyzeResume(r.Context(), req.APIKey, text, "")
+	result, err := h.analyzer.Analyze(ctx, req.APIKey, doc.Text, analyzer.Options{
+		Model:          req.Model,
+		Jurisdiction:   req.Jurisdiction,
+		SkipRedaction:  req.SkipRedaction,
+		NoCache:        req.NoCache,
+		Email:          doc.Email,
+		Clauses:        doc.Clauses,
+		TrackedChanges: doc.TrackedChanges,
+		Metadata:       doc.Metadata,
+		Contacts:       doc.Contacts,
+	})
+	if err != nil {
+		log.Printf("Analysis error: %v", err)
+		h.metrics.RecordError(models.JobTypeAnalyze)
+		return nil, err
+	}
+	h.metrics.Record(models.JobTypeAnalyze, result.Usage, result.CacheStatus == cache.StatusHit)
+	result.Document = documentInfo(req.Filename, doc)
+
+	if result.Partial {
+		log.Printf("Analysis partial: Risk Score %d (%s)", result.RiskScore, result.RiskLevel)
+	} else {
+		log.Printf("Analysis complete: Risk Score %d (%s) after %d attempt(s)", result.RiskScore, result.RiskLevel, result.Attempts)
+	}
+
+	return result, nil
+}
+
+// runResume analyzes a prepared resume
+func (h *Handler) runResume(ctx context.Context, req *models.ResumeAnalyzeRequest, doc *parser.Document) (*models.ResumeAnalysisResult, error) {
+	// Analyze resume with the configured provider
+	log.Printf("Analyzing resume: %s (%s, %d chars)", req.Filename, doc.Format, len(doc.Text))
+
+	result, err := h.resumeAnalyzer.AnalyzeResume(ctx, req.APIKey, doc.Text, analyzer.Options{Model: req.Model, NoCache: req.NoCache})
 	if err != nil {
 		log.Printf("Resume analysis error: %v", err)
-		sendError(w, http.StatusInternalServerError, "Resume analysis failed", err.Error())
-		return
+		h.metrics.RecordError(models.JobTypeResumeAnalyze)
+		return nil, err
 	}
+	h.metrics.Record(models.JobTypeResumeAnalyze, result.Usage, result.CacheStatus == cache.StatusHit)
+	result.Document = documentInfo(req.Filename, doc)
 
-	log.Printf("Resume analysis complete: Score %d (%s)", result.OverallScore, result.ScoreCategory)
+	log.Printf("Resume analysis complete: Score %d (%s) after %d attempt(s)", result.OverallScore, result.ScoreCategory, result.Attempts)
 
-	// Send response
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(result)
+	return result, nil
+}
+
+// documentInfo describes a parsed document for the response
+func documentInfo(filename string, doc *parser.Document) *models.DocumentInfo {
+	return &models.DocumentInfo{
+		Filename:       filename,
+		Format:         string(doc.Format),
+		Email:          doc.Email,
+		Attachments:    doc.Attachments,
+		Clauses:        doc.Clauses,
+		TrackedChanges: doc.TrackedChanges,
+		Pages:          doc.Pages,
+		EmptyPages:     doc.EmptyPages,
+		Metadata:       doc.Metadata,
+	}
+}
+
+// parseErrorStatus maps a parser error to an HTTP status code
+func parseErrorStatus(err error) int {
+	var mismatchErr *parser.MismatchError
+	var unsupportedErr *parser.UnsupportedFormatError
+	if errors.As(err, &mismatchErr) || errors.As(err, &unsupportedErr) {
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusBadRequest
+}
+
+// analysisErrorStatus maps an analyzer error to an HTTP status code
+func analysisErrorStatus(err error) int {
+	var validationErr *analyzer.ValidationError
+	if errors.As(err, &validationErr) {
+		// The model answered, but not in the required shape
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
 }
 
 // setCORSHeaders sets CORS headers for frontend access
 func setCORSHeaders(w http.ResponseWriter) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Access-Control-Expose-Headers", "Cache-Status, Location, Retry-After")
+}
+
+// setCacheStatus sets the RFC 9211 Cache-Status header for a cache status
+func setCacheStatus(w http.ResponseWriter, status string) {
+	switch status {
+	case cache.StatusHit:
+		w.Header().Set("Cache-Status", "ea-scanner; hit")
+	case cache.StatusMiss:
+		w.Header().Set("Cache-Status", "ea-scanner; fwd=uri-miss; stored")
+	case cache.StatusNotStored:
+		w.Header().Set("Cache-Status", `ea-scanner; fwd=uri-miss; detail="not stored"`)
+	case cache.StatusBypass:
+		w.Header().Set("Cache-Status", "ea-scanner; fwd=bypass")
+	}
 }
 
 // sendError sends an error response
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"ea-scanner/internal/jobs"
+	"ea-scanner/internal/models"
+)
+
+// handleCreateJob validates an analysis request and queues it
+func (h *Handler) handleCreateJob(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	w.Header().Set("Content-Type", "application/json")
+
+	var jobReq models.JobRequest
+	if err := json.NewDecoder(r.Body).Decode(&jobReq); err != nil {
+		sendError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+	if len(jobReq.Request) == 0 {
+		sendError(w, http.StatusBadRequest, "Request is required", "")
+		return
+	}
+
+	// Validate and parse the document now so bad input fails fast; the API
+	// key only lives in the job closure and is never stored
+	var run jobs.Func
+	switch jobReq.Type {
+	case models.JobTypeAnalyze:
+		var req models.AnalyzeRequest
+		if err := decodeStrict(jobReq.Request, &req); err != nil {
+			sendError(w, http.StatusBadRequest, "Invalid analyze request", err.Error())
+			return
+		}
+		doc, reqErr := h.prepareAnalyze(&req)
+		if reqErr != nil {
+			sendError(w, reqErr.status, reqErr.message, reqErr.details)
+			return
+		}
+		run = func(ctx context.Context) (any, error) {
+			return h.runAnalyze(ctx, &req, doc)
+		}
+	case models.JobTypeResumeAnalyze:
+		var req models.ResumeAnalyzeRequest
+		if err := decodeStrict(jobReq.Request, &req); err != nil {
+			sendError(w, http.StatusBadRequest, "Invalid resume analyze request", err.Error())
+			return
+		}
+		doc, reqErr := h.prepareResume(&req)
+		if reqErr != nil {
+			sendError(w, reqErr.status, reqErr.message, reqErr.details)
+			return
+		}
+		run = func(ctx context.Context) (any, error) {
+			return h.runResume(ctx, &req, doc)
+		}
+	default:
+		sendError(w, http.StatusBadRequest, "Unknown job type", `Expected "analyze" or "resume_analyze"`)
+		return
+	}
+
+	job, err := h.jobs.Submit(r.Context(), jobReq.Type, run)
+	if errors.Is(err, jobs.ErrQueueFull) {
+		w.Header().Set("Retry-After", "30")
+		sendError(w, http.StatusServiceUnavailable, "Job queue is full", "Try again later")
+		return
+	}
+	if errors.Is(err, jobs.ErrClosed) {
+		sendError(w, http.StatusServiceUnavailable, "Server is shutting down", "Try again later")
+		return
+	}
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to create job", err.Error())
+		return
+	}
+
+	w.Header().Set("Location", "/api/jobs/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// handleGetJob returns a job's status and, once finished, its result
+func (h *Handler) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	w.Header().Set("Content-Type", "application/json")
+
+	job, err := h.jobs.Get(r.Context(), r.PathValue("id"))
+	if errors.Is(err, jobs.ErrNotFound) {
+		sendError(w, http.StatusNotFound, "Job not found", "")
+		return
+	}
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "Failed to load job", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(job)
+}
+
+// handleCancelJob cancels a queued or running job
+func (h *Handler) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+	w.Header().Set("Content-Type", "application/json")
+
+	job, err := h.jobs.Cancel(r.Context(), r.PathValue("id"))
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		sendError(w, http.StatusNotFound, "Job not found", "")
+		return
+	case errors.Is(err, jobs.ErrFinished):
+		sendError(w, http.StatusConflict, "Job has already finished", string(job.Status))
+		return
+	case err != nil:
+		sendError(w, http.StatusInternalServerError, "Failed to cancel job", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(job)
+}
+
+// decodeStrict decodes JSON, rejecting unknown fields
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
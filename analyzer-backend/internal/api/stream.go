@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"ea-scanner/internal/analyzer"
+	"ea-scanner/internal/models"
+	"ea-scanner/internal/parser"
+)
+
+// keepAliveInterval is how often an idle stream sends a comment so proxies
+// do not drop it during long model calls
+const keepAliveInterval = 15 * time.Second
+
+// sseWriter writes Server-Sent Events; it is safe for concurrent use
+type sseWriter struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// newSSEWriter sets the SSE headers and returns a writer, or nil if the
+// response cannot be streamed
+func newSSEWriter(w http.ResponseWriter) *sseWriter {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &sseWriter{w: w, flusher: flusher}
+}
+
+// send writes a single event named after its stage
+func (s *sseWriter) send(event analyzer.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		data, _ = json.Marshal(analyzer.Event{Stage: analyzer.StageError, Message: err.Error()})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event.Stage, data)
+	s.flusher.Flush()
+}
+
+// keepAlive sends an SSE comment every interval until ctx is done
+func (s *sseWriter) keepAlive(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			fmt.Fprint(s.w, ": keep-alive\n\n")
+			s.flusher.Flush()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// startKeepAlive starts keep-alive comments for the stream. The returned
+// function stops them and must be called before the handler returns.
+func (s *sseWriter) startKeepAlive(ctx context.Context) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.keepAlive(ctx, keepAliveInterval)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// handleAnalyzeStream analyzes a document, streaming progress as it goes
+func (h *Handler) handleAnalyzeStream(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	// Parse request body
+	var req models.AnalyzeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		sendError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	// Request errors are reported as plain JSON before the stream starts
+	doc, reqErr := h.prepareAnalyze(&req)
+	if reqErr != nil {
+		w.Header().Set("Content-Type", "application/json")
+		sendError(w, reqErr.status, reqErr.message, reqErr.details)
+		return
+	}
+
+	stream := newSSEWriter(w)
+	if stream == nil {
+		w.Header().Set("Content-Type", "application/json")
+		sendError(w, http.StatusInternalServerError, "Streaming not supported", "")
+		return
+	}
+
+	stopKeepAlive := stream.startKeepAlive(r.Context())
+	defer stopKeepAlive()
+
+	stream.send(analyzer.Event{Stage: analyzer.StageParsed, Message: "Document parsed", Data: parsedInfo(req.Filename, doc)})
+
+	ctx := analyzer.WithProgress(r.Context(), stream.send)
+	result, err := h.runAnalyze(ctx, &req, doc)
+	if err != nil {
+		stream.send(analyzer.Event{Stage: analyzer.StageError, Message: "Analysis failed", Data: models.ErrorResponse{Error: "Analysis failed", Details: err.Error()}})
+		return
+	}
+
+	stream.send(analyzer.Event{Stage: analyzer.StageResult, Data: result})
+}
+
+// handleResumeAnalyzeStream analyzes a resume, streaming progress as it goes
+func (h *Handler) handleResumeAnalyzeStream(w http.ResponseWriter, r *http.Request) {
+	setCORSHeaders(w)
+
+	// Parse request body
+	var req models.ResumeAnalyzeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		sendError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	// Request errors are reported as plain JSON before the stream starts
+	doc, reqErr := h.prepareResume(&req)
+	if reqErr != nil {
+		w.Header().Set("Content-Type", "application/json")
+		sendError(w, reqErr.status, reqErr.message, reqErr.details)
+		return
+	}
+
+	stream := newSSEWriter(w)
+	if stream == nil {
+		w.Header().Set("Content-Type", "application/json")
+		sendError(w, http.StatusInternalServerError, "Streaming not supported", "")
+		return
+	}
+
+	stopKeepAlive := stream.startKeepAlive(r.Context())
+	defer stopKeepAlive()
+
+	stream.send(analyzer.Event{Stage: analyzer.StageParsed, Message: "Resume parsed", Data: parsedInfo(req.Filename, doc)})
+
+	ctx := analyzer.WithProgress(r.Context(), stream.send)
+	result, err := h.runResume(ctx, &req, doc)
+	if err != nil {
+		stream.send(analyzer.Event{Stage: analyzer.StageError, Message: "Resume analysis failed", Data: models.ErrorResponse{Error: "Resume analysis failed", Details: err.Error()}})
+		return
+	}
+
+	stream.send(analyzer.Event{Stage: analyzer.StageResult, Data: result})
+}
+
+// parsedInfo describes a parsed document for the StageParsed event
+func parsedInfo(filename string, doc *parser.Document) map[string]any {
+	return map[string]any{
+		"filename":   filename,
+		"format":     doc.Format,
+		"characters": utf8.RuneCountInString(doc.Text),
+	}
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned by Get when there is no live entry for the key
+var ErrMiss = errors.New("cache miss")
+
+// Statuses reported for a cacheable request
+const (
+	StatusHit       = "hit"        // Served from the cache
+	StatusMiss      = "miss"       // Computed and stored
+	StatusNotStored = "not_stored" // Computed but not stored, e.g. a partial result
+	StatusBypass    = "bypass"     // The client asked not to use the cache
+)
+
+// Store persists cached values
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Key derives a cache key from its parts. Parts are length-prefixed so
+// different splits of the same bytes give different keys.
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		var size [8]byte
+		binary.LittleEndian.PutUint64(size[:], uint64(len(part)))
+		h.Write(size[:])
+		h.Write([]byte(part))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestKey(t *testing.T) {
+	if Key("ab", "c") == Key("a", "bc") {
+		t.Error("Key() is the same for different splits of the same bytes")
+	}
+	if Key("a", "b") != Key("a", "b") {
+		t.Error("Key() differs for the same parts")
+	}
+}
+
+func TestStores(t *testing.T) {
+	sqlite, err := NewSQLiteStore(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlite.Close()
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(10),
+		"sqlite": sqlite,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrMiss) {
+				t.Errorf("Get(missing) error = %v, want ErrMiss", err)
+			}
+
+			if err := store.Set(ctx, "live", []byte("v1"), time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Set(ctx, "live", []byte("v2"), time.Hour); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := store.Get(ctx, "live"); err != nil || string(got) != "v2" {
+				t.Errorf("Get(live) = %q, %v; want v2", got, err)
+			}
+
+			if err := store.Set(ctx, "short", []byte("v"), 10*time.Millisecond); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(20 * time.Millisecond)
+			if _, err := store.Get(ctx, "short"); !errors.Is(err, ErrMiss) {
+				t.Errorf("Get(expired) error = %v, want ErrMiss", err)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(2)
+
+	store.Set(ctx, "a", []byte("a"), time.Hour)
+	store.Set(ctx, "b", []byte("b"), time.Hour)
+	store.Get(ctx, "a") // b is now the least recently used
+	store.Set(ctx, "c", []byte("c"), time.Hour)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, err := store.Get(ctx, key); (err == nil) != want {
+			t.Errorf("Get(%s) error = %v, want present = %v", key, err, want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS cache (
+	key        TEXT PRIMARY KEY,
+	value      BLOB NOT NULL,
+	expires_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS cache_expires ON cache (expires_at);`
+
+// SQLiteStore persists cached values in a SQLite database so they survive
+// restarts
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (or creates) the SQLite database at path
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache database: %w", err)
+	}
+	// SQLite allows a single writer
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create cache schema: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Get returns the value for key
+func (s *SQLiteStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT value FROM cache WHERE key = ? AND expires_at > ?`, key, time.Now().UnixMilli()).
+		Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+	return value, nil
+}
+
+// Set stores value for key and removes expired entries
+func (s *SQLiteStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	now := time.Now()
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO cache (key, value, expires_at) VALUES (?, ?, ?)`,
+		key, value, now.Add(ttl).UnixMilli()); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM cache WHERE expires_at <= ?`, now.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to prune cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package diff
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"ea-scanner/internal/models"
+)
+
+// modifiedThreshold is the similarity above which a removed and an added
+// clause are reported as one modified clause
+const modifiedThreshold = 0.5
+
+// sentencePattern splits text into sentences
+var sentencePattern = regexp.MustCompile(`[^.!?]+(?:[.!?]+["')\]]?|$)`)
+
+// Clause is a unit of text aligned between two documents
+type Clause struct {
+	ID    string // ID of the clause in its document's clause tree
+	Label string // Clause number or heading, if any
+	Text  string
+}
+
+// Flatten lists the clauses of a clause tree in document order, each with
+// its heading and its own body. Trees with little structure fall back to
+// sentences, which keep the ID of the clause they belong to.
+func Flatten(tree []*models.Clause) []Clause {
+	var clauses []Clause
+	var walk func(nodes []*models.Clause)
+	walk = func(nodes []*models.Clause) {
+		for _, n := range nodes {
+			text := strings.TrimSpace(n.Label + " " + n.Body)
+			if n.Title != "" {
+				text = strings.TrimSpace(strings.TrimSpace(n.Label+" "+n.Title) + "\n" + n.Body)
+			}
+			if text != "" {
+				clauses = append(clauses, Clause{ID: n.ID, Label: n.Label, Text: text})
+			}
+			walk(n.Children)
+		}
+	}
+	walk(tree)
+
+	if len(clauses) < 3 {
+		var sentences []Clause
+		for _, c := range clauses {
+			for _, s := range sentencePattern.FindAllString(c.Text, -1) {
+				if s = strings.TrimSpace(s); s != "" {
+					sentences = append(sentences, Clause{ID: c.ID, Text: s})
+				}
+			}
+		}
+		clauses = sentences
+	}
+
+	return clauses
+}
+
+// body returns the clause text without its number, so renumbered clauses
+// still match
+func (c Clause) body() string {
+	return strings.TrimSpace(strings.TrimPrefix(c.Text, c.Label))
+}
+
+// Compare aligns the clauses of two clause trees and returns the changes,
+// along with a map from original clause IDs to the IDs of the revised
+// clauses they were aligned with (unchanged, renumbered or modified)
+func Compare(original, revised []*models.Clause) ([]models.ClauseChange, map[string]string) {
+	a, b := Flatten(original), Flatten(revised)
+	keysA, keysB := clauseKeys(a), clauseKeys(b)
+
+	changes := []models.ClauseChange{}
+	aligned := map[string]string{}
+	var removed, added []Clause
+
+	// Walk the longest common subsequence of identical clauses (renumbered
+	// clauses count as identical and are not reported); clauses
+	// between matches are candidates for removed/added/modified
+	i, j := 0, 0
+	for _, pair := range lcs(keysA, keysB) {
+		removed = append(removed, a[i:pair[0]]...)
+		added = append(added, b[j:pair[1]]...)
+		changes = append(changes, pairChanges(removed, added, aligned)...)
+		removed, added = nil, nil
+		alignIDs(aligned, a[pair[0]].ID, b[pair[1]].ID)
+		i, j = pair[0]+1, pair[1]+1
+	}
+	removed = append(removed, a[i:]...)
+	added = append(added, b[j:]...)
+	changes = append(changes, pairChanges(removed, added, aligned)...)
+
+	return changes, aligned
+}
+
+// alignIDs records that an original clause corresponds to a revised one;
+// the first alignment of a clause wins
+func alignIDs(aligned map[string]string, original, revised string) {
+	if _, ok := aligned[original]; !ok && original != "" && revised != "" {
+		aligned[original] = revised
+	}
+}
+
+// pairChanges greedily pairs removed and added clauses into modifications,
+// recording the pairs in aligned
+func pairChanges(removed, added []Clause, aligned map[string]string) []models.ClauseChange {
+	var changes []models.ClauseChange
+	used := make([]bool, len(added))
+
+	for _, r := range removed {
+		best, bestScore := -1, modifiedThreshold
+		for k, ad := range added {
+			if used[k] {
+				continue
+			}
+			score := Similarity(r.body(), ad.body())
+			if r.Label != "" && r.Label == ad.Label {
+				// Same clause number: likely the same clause reworded
+				score += 0.2
+			}
+			if score >= bestScore {
+				best, bestScore = k, score
+			}
+		}
+
+		if best < 0 {
+			changes = append(changes, models.ClauseChange{Type: models.ChangeRemoved, ClauseID: r.ID, Label: r.Label, Original: r.Text})
+			continue
+		}
+		used[best] = true
+		alignIDs(aligned, r.ID, added[best].ID)
+		label := added[best].Label
+		if label == "" {
+			label = r.Label
+		}
+		changes = append(changes, models.ClauseChange{
+			Type:       models.ChangeModified,
+			ClauseID:   added[best].ID,
+			Label:      label,
+			Original:   r.Text,
+			Revised:    added[best].Text,
+			Similarity: roundTo(Similarity(r.body(), added[best].body()), 2),
+		})
+	}
+
+	for k, ad := range added {
+		if !used[k] {
+			changes = append(changes, models.ClauseChange{Type: models.ChangeAdded, ClauseID: ad.ID, Label: ad.Label, Revised: ad.Text})
+		}
+	}
+
+	return changes
+}
+
+// lcs returns index pairs of the longest common subsequence of a and b
+func lcs(a, b []string) [][2]int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case dp[i+1][j] >= dp[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// clauseKeys returns a comparison key per clause that ignores case,
+// punctuation and whitespace
+func clauseKeys(clauses []Clause) []string {
+	keys := make([]string, len(clauses))
+	for i, c := range clauses {
+		keys[i] = strings.Join(words(c.body()), " ")
+	}
+	return keys
+}
+
+// Similarity returns the Dice coefficient of the word sets of a and b (0-1)
+func Similarity(a, b string) float64 {
+	wa, wb := words(a), words(b)
+	if len(wa) == 0 || len(wb) == 0 {
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, w := range wa {
+		counts[w]++
+	}
+	common := 0
+	for _, w := range wb {
+		if counts[w] > 0 {
+			counts[w]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(wa)+len(wb))
+}
+
+// words lowercases text and splits it into words, dropping punctuation
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func roundTo(f float64, places int) float64 {
+	p := 1.0
+	for i := 0; i < places; i++ {
+		p *= 10
+	}
+	return float64(int(f*p+0.5)) / p
+}
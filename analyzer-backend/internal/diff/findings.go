@@ -0,0 +1,89 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+
+	"ea-scanner/internal/models"
+)
+
+// findingMatchThreshold is the quote similarity at which two findings are
+// considered the same issue
+const findingMatchThreshold = 0.6
+
+// Findings compares the findings of two analyses. Each original finding is
+// matched with at most one revised finding, by quote similarity or, for
+// findings of the same category, by the clauses aligned in clauseIDs (original
+// clause ID to revised clause ID, as returned by Compare). Fixed findings have
+// no match in the revision, introduced findings have none in the original,
+// and matched findings whose severity differs are reported as severity changes.
+func Findings(original, revised []models.Finding, clauseIDs map[string]string) (fixed, introduced []models.Finding, changed []models.SeverityChange) {
+	fixed, introduced, changed = []models.Finding{}, []models.Finding{}, []models.SeverityChange{}
+	matched := make([]bool, len(revised))
+
+	for _, f := range original {
+		best, bestScore := -1, 0.0
+		for k, other := range revised {
+			if matched[k] {
+				continue
+			}
+			if score := matchScore(f, other, clauseIDs); score > bestScore {
+				best, bestScore = k, score
+			}
+		}
+
+		if best < 0 {
+			fixed = append(fixed, f)
+			continue
+		}
+		matched[best] = true
+		if !strings.EqualFold(f.Severity, revised[best].Severity) {
+			changed = append(changed, models.SeverityChange{Original: f, Revised: revised[best]})
+		}
+	}
+
+	for k, f := range revised {
+		if !matched[k] {
+			introduced = append(introduced, f)
+		}
+	}
+
+	return fixed, introduced, changed
+}
+
+// matchScore rates how likely two findings describe the same issue, from 0
+// (different issues) to 2. Findings from the same rule match; similar quotes
+// match; findings of the same category also match when they sit in aligned
+// clauses or, for findings about absent terms that have no quote, when their
+// descriptions are the same.
+func matchScore(f, other models.Finding, clauseIDs map[string]string) float64 {
+	if f.RuleID != "" && strings.EqualFold(f.RuleID, other.RuleID) {
+		return 2
+	}
+
+	score := 0.0
+	if f.Quote != "" && other.Quote != "" {
+		if similarity := Similarity(f.Quote, other.Quote); similarity >= findingMatchThreshold {
+			score = similarity
+		}
+	}
+
+	if sameCategory(f, other) {
+		if f.ClauseID != "" && clauseIDs[f.ClauseID] == other.ClauseID {
+			score = max(score, findingMatchThreshold)
+		}
+		if f.Quote == "" && other.Quote == "" && slices.Equal(words(f.Description), words(other.Description)) {
+			score = max(score, 1)
+		}
+		if score > 0 {
+			// Prefer a candidate of the same category among similar quotes
+			score++
+		}
+	}
+	return score
+}
+
+// sameCategory reports whether two findings have the same category
+func sameCategory(f, other models.Finding) bool {
+	return strings.EqualFold(strings.TrimSpace(f.Category), strings.TrimSpace(other.Category))
+}
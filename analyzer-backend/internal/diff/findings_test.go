@@ -0,0 +1,89 @@
+package diff
+
+import (
+	"testing"
+
+	"ea-scanner/internal/models"
+)
+
+func TestFindings(t *testing.T) {
+	nonCompete := models.Finding{Category: "Non-Compete", Severity: "HIGH", Quote: "Employee shall not work for any competitor worldwide for five years", ClauseID: "c7"}
+	nonSolicit := models.Finding{Category: "Non-Compete", Severity: "MEDIUM", Quote: "Employee shall not solicit clients of the Company", ClauseID: "c8"}
+
+	tests := []struct {
+		name           string
+		original       []models.Finding
+		revised        []models.Finding
+		clauseIDs      map[string]string
+		wantFixed      int
+		wantIntroduced int
+		wantChanged    int
+	}{
+		{
+			name:     "unchanged",
+			original: []models.Finding{nonCompete},
+			revised:  []models.Finding{nonCompete},
+		},
+		{
+			name:      "fixed despite another finding of the same category",
+			original:  []models.Finding{nonCompete, nonSolicit},
+			revised:   []models.Finding{nonSolicit},
+			wantFixed: 1,
+		},
+		{
+			name:           "same category in an unrelated clause",
+			original:       []models.Finding{nonCompete},
+			revised:        []models.Finding{{Category: "non-compete", Severity: "HIGH", Quote: "No outside employment during evenings", ClauseID: "c2"}},
+			clauseIDs:      map[string]string{"c7": "c9"},
+			wantFixed:      1,
+			wantIntroduced: 1,
+		},
+		{
+			name:        "reworded in the aligned clause with lower severity",
+			original:    []models.Finding{nonCompete},
+			revised:     []models.Finding{{Category: "Non-Compete", Severity: "LOW", Quote: "Employee may not join a direct competitor in the city for six months", ClauseID: "c9"}},
+			clauseIDs:   map[string]string{"c7": "c9"},
+			wantChanged: 1,
+		},
+		{
+			name:        "similar quote with changed severity",
+			original:    []models.Finding{nonCompete},
+			revised:     []models.Finding{{Category: "Restrictive Covenant", Severity: "LOW", Quote: "Employee shall not work for any competitor worldwide for five years."}},
+			wantChanged: 1,
+		},
+		{
+			name:     "unquoted finding with the same description",
+			original: []models.Finding{{Category: "Missing Notice Period", Severity: "MEDIUM", Description: "The agreement does not state a notice period."}},
+			revised:  []models.Finding{{Category: "Missing Notice Period", Severity: "MEDIUM", Description: "The agreement does not state a notice period"}},
+		},
+		{
+			name:           "unquoted finding with another description",
+			original:       []models.Finding{{Category: "Missing Term", Severity: "MEDIUM", Description: "The agreement does not state a notice period."}},
+			revised:        []models.Finding{{Category: "Missing Term", Severity: "MEDIUM", Description: "The agreement does not state a probation period."}},
+			wantFixed:      1,
+			wantIntroduced: 1,
+		},
+		{
+			name:        "same rule with another description",
+			original:    []models.Finding{{Category: "Gratuity", Severity: "MEDIUM", Description: "Gratuity is payable after 5 years of service, not 7.", RuleID: "IN-GRATUITY"}},
+			revised:     []models.Finding{{Category: "Gratuity Forfeiture", Severity: "HIGH", Description: "Gratuity is forfeited on resignation.", RuleID: "IN-GRATUITY"}},
+			wantChanged: 1,
+		},
+		{
+			name:           "each finding matches once",
+			original:       []models.Finding{nonCompete},
+			revised:        []models.Finding{nonCompete, nonCompete},
+			wantIntroduced: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixed, introduced, changed := Findings(tt.original, tt.revised, tt.clauseIDs)
+			if len(fixed) != tt.wantFixed || len(introduced) != tt.wantIntroduced || len(changed) != tt.wantChanged {
+				t.Errorf("Findings() = %d fixed, %d introduced, %d changed; want %d, %d, %d",
+					len(fixed), len(introduced), len(changed), tt.wantFixed, tt.wantIntroduced, tt.wantChanged)
+			}
+		})
+	}
+}
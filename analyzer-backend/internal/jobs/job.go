@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Status is the lifecycle state of a job
+type Status string
+
+// Job statuses
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+// Finished reports whether the status is terminal
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCanceled
+}
+
+// ErrNotFound is returned when a job does not exist
+var ErrNotFound = errors.New("job not found")
+
+// Job is an asynchronous analysis job
+type Job struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`             // e.g. "analyze", "resume_analyze"
+	Status    Status          `json:"status"`           // queued/running/succeeded/failed/canceled
+	Result    json.RawMessage `json:"result,omitempty"` // Analysis result once succeeded
+	Error     string          `json:"error,omitempty"`  // Failure reason
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Store persists jobs
+type Store interface {
+	// Create stores a new job
+	Create(ctx context.Context, job *Job) error
+
+	// Get returns a job by ID, or ErrNotFound
+	Get(ctx context.Context, id string) (*Job, error)
+
+	// Update replaces a stored job
+	Update(ctx context.Context, job *Job) error
+
+	// Delete removes a job; deleting a missing job is not an error
+	Delete(ctx context.Context, id string) error
+
+	// Prune deletes finished jobs last updated before the given time
+	Prune(ctx context.Context, before time.Time) (int, error)
+
+	// FailUnfinished marks every queued or running job as failed. It is used
+	// at startup for jobs orphaned by a restart.
+	FailUnfinished(ctx context.Context, reason string) (int, error)
+}
+
+// newID returns a random job ID
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
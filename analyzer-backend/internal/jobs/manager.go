@@ -0,0 +1,328 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull is returned by Submit when the queue is at capacity
+var ErrQueueFull = errors.New("job queue is full")
+
+// ErrFinished is returned by Cancel when the job has already finished
+var ErrFinished = errors.New("job has already finished")
+
+// ErrClosed is returned by Submit once the manager is shutting down
+var ErrClosed = errors.New("job manager is shutting down")
+
+// shutdownReason is recorded for jobs that did not finish before Close gave up
+const shutdownReason = "interrupted by server shutdown"
+
+// Func runs a job and returns its JSON-encodable result
+type Func func(ctx context.Context) (any, error)
+
+// Config holds worker pool configuration
+type Config struct {
+	Workers    int           // Jobs run concurrently
+	QueueDepth int           // Jobs waiting for a worker before Submit fails
+	Timeout    time.Duration // Maximum run time per job; zero or less for none
+	Retention  time.Duration // How long finished jobs are kept
+}
+
+// DefaultConfig returns default configuration
+func DefaultConfig() Config {
+	return Config{
+		Workers:    4,
+		QueueDepth: 100,
+		Timeout:    10 * time.Minute,
+		Retention:  24 * time.Hour,
+	}
+}
+
+type task struct {
+	id  string
+	fn  Func
+	ctx context.Context
+}
+
+// Manager runs jobs on an in-process worker pool and records them in a Store
+type Manager struct {
+	store  Store
+	config Config
+	queue  chan *task
+
+	mu      sync.Mutex // Serializes status transitions and submissions
+	cancels map[string]context.CancelFunc
+	closed  bool
+
+	ctx     context.Context // Canceled when Close stops waiting for jobs
+	stop    context.CancelFunc
+	done    chan struct{} // Closed when Close is called
+	workers sync.WaitGroup
+	wg      sync.WaitGroup
+}
+
+// NewManager creates a Manager and starts its workers. Jobs left unfinished
+// in the store by a previous process are marked as failed. The manager owns
+// the store and closes it on Close if it is an io.Closer.
+func NewManager(store Store, config Config) *Manager {
+	ctx, stop := context.WithCancel(context.Background())
+	m := &Manager{
+		store:   store,
+		config:  config,
+		queue:   make(chan *task, max(config.QueueDepth, 0)),
+		cancels: map[string]context.CancelFunc{},
+		ctx:     ctx,
+		stop:    stop,
+		done:    make(chan struct{}),
+	}
+
+	if n, err := store.FailUnfinished(ctx, "interrupted by server restart"); err != nil {
+		log.Printf("Failed to clean up unfinished jobs: %v", err)
+	} else if n > 0 {
+		log.Printf("Marked %d unfinished job(s) as failed", n)
+	}
+
+	for i := 0; i < max(config.Workers, 1); i++ {
+		m.workers.Add(1)
+		go m.worker()
+	}
+	if config.Retention > 0 {
+		m.wg.Add(1)
+		go m.pruner()
+	}
+
+	return m
+}
+
+// Close shuts the manager down. It stops accepting jobs and lets the workers
+// finish the running and queued ones until ctx is done, then cancels those
+// left and marks them as failed. Finally it closes the store.
+func (m *Manager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	close(m.queue)
+	close(m.done)
+	m.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		log.Printf("Canceling unfinished jobs: %v", ctx.Err())
+		m.stop()
+		<-drained
+	}
+	m.stop()
+	m.wg.Wait()
+
+	// Jobs whose status could not be recorded after the cancellation
+	if n, err := m.store.FailUnfinished(context.Background(), shutdownReason); err != nil {
+		log.Printf("Failed to mark unfinished jobs: %v", err)
+	} else if n > 0 {
+		log.Printf("Marked %d unfinished job(s) as failed", n)
+	}
+
+	if closer, ok := m.store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Submit queues a job of the given type
+func (m *Manager) Submit(ctx context.Context, jobType string, fn Func) (*Job, error) {
+	now := time.Now().UTC()
+	job := &Job{
+		ID:        newID(),
+		Type:      jobType,
+		Status:    StatusQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := m.store.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	taskCtx, cancel := context.WithCancel(m.ctx)
+	m.mu.Lock()
+	m.cancels[job.ID] = cancel
+	queueErr := ErrClosed
+	if !m.closed {
+		select {
+		case m.queue <- &task{id: job.ID, fn: fn, ctx: taskCtx}:
+			queueErr = nil
+		default:
+			queueErr = ErrQueueFull
+		}
+	}
+	if queueErr != nil {
+		delete(m.cancels, job.ID)
+	}
+	m.mu.Unlock()
+	if queueErr == nil {
+		return job, nil
+	}
+
+	// The client never learns the job ID, so do not keep a record of it
+	cancel()
+	if err := m.store.Delete(ctx, job.ID); err != nil {
+		log.Printf("Failed to delete rejected job %s: %v", job.ID, err)
+	}
+	return nil, queueErr
+}
+
+// Get returns a job by ID
+func (m *Manager) Get(ctx context.Context, id string) (*Job, error) {
+	return m.store.Get(ctx, id)
+}
+
+// Cancel cancels a queued or running job
+func (m *Manager) Cancel(ctx context.Context, id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status.Finished() {
+		return job, ErrFinished
+	}
+
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+		delete(m.cancels, id)
+	}
+
+	job.Status = StatusCanceled
+	job.UpdatedAt = time.Now().UTC()
+	if err := m.store.Update(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (m *Manager) worker() {
+	defer m.workers.Done()
+	for t := range m.queue {
+		m.run(t)
+	}
+}
+
+// run executes a task unless it was canceled while queued
+func (m *Manager) run(t *task) {
+	if !m.start(t.id) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(t.ctx)
+	if m.config.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(t.ctx, m.config.Timeout)
+	}
+	defer cancel()
+
+	result, err := t.fn(ctx)
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	m.finish(t.id, result, err)
+}
+
+// start moves a queued job to running. It returns false if the job should
+// not run (e.g. it was canceled while queued).
+func (m *Manager) start(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.store.Get(m.ctx, id)
+	if err != nil || job.Status != StatusQueued {
+		delete(m.cancels, id)
+		return false
+	}
+
+	job.Status = StatusRunning
+	job.UpdatedAt = time.Now().UTC()
+	if err := m.store.Update(m.ctx, job); err != nil {
+		log.Printf("Failed to start job %s: %v", id, err)
+		return false
+	}
+	return true
+}
+
+// finish records a job's outcome unless it was canceled in the meantime
+func (m *Manager) finish(id string, result any, runErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+		delete(m.cancels, id)
+	}
+
+	// Use a fresh context: the manager context may already be canceled on shutdown
+	ctx := context.Background()
+	job, err := m.store.Get(ctx, id)
+	if err != nil || job.Status.Finished() {
+		return
+	}
+
+	switch {
+	case runErr != nil:
+		job.Status = StatusFailed
+		job.Error = runErr.Error()
+		switch {
+		case m.ctx.Err() != nil && errors.Is(runErr, context.Canceled):
+			job.Error = shutdownReason
+		case errors.Is(runErr, context.DeadlineExceeded):
+			job.Error = fmt.Sprintf("job timed out after %s", m.config.Timeout)
+		}
+	default:
+		data, err := json.Marshal(result)
+		if err != nil {
+			job.Status = StatusFailed
+			job.Error = fmt.Sprintf("failed to encode result: %v", err)
+			break
+		}
+		job.Status = StatusSucceeded
+		job.Result = data
+	}
+
+	job.UpdatedAt = time.Now().UTC()
+	if err := m.store.Update(ctx, job); err != nil {
+		log.Printf("Failed to record result of job %s: %v", id, err)
+	}
+}
+
+// pruner periodically deletes finished jobs older than the retention period
+func (m *Manager) pruner() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(min(m.config.Retention, 10*time.Minute))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			n, err := m.store.Prune(m.ctx, time.Now().Add(-m.config.Retention))
+			if err != nil {
+				log.Printf("Failed to prune jobs: %v", err)
+			} else if n > 0 {
+				log.Printf("Pruned %d finished job(s)", n)
+			}
+		}
+	}
+}
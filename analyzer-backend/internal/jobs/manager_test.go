@@ -0,0 +1,93 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestManagerCloseDrainsQueue(t *testing.T) {
+	store := NewMemoryStore()
+	m := NewManager(store, Config{Workers: 1, QueueDepth: 10})
+
+	var ids []string
+	for range 3 {
+		job, err := m.Submit(context.Background(), "analyze", func(ctx context.Context) (any, error) {
+			time.Sleep(5 * time.Millisecond)
+			return "done", nil
+		})
+		if err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+		ids = append(ids, job.ID)
+	}
+
+	if err := m.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	for _, id := range ids {
+		job, err := store.Get(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != StatusSucceeded {
+			t.Errorf("job %s status = %s, want %s", id, job.Status, StatusSucceeded)
+		}
+	}
+
+	if _, err := m.Submit(context.Background(), "analyze", nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit() after Close error = %v, want ErrClosed", err)
+	}
+}
+
+func TestManagerCloseDeadline(t *testing.T) {
+	store := NewMemoryStore()
+	m := NewManager(store, Config{Workers: 1, QueueDepth: 10})
+
+	block := func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	running, err := m.Submit(context.Background(), "analyze", block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queued, err := m.Submit(context.Background(), "analyze", block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := m.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	for _, id := range []string{running.ID, queued.ID} {
+		job, err := store.Get(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != StatusFailed || job.Error != shutdownReason {
+			t.Errorf("job %s = %s %q, want %s %q", id, job.Status, job.Error, StatusFailed, shutdownReason)
+		}
+	}
+}
+
+func TestManagerNoTimeout(t *testing.T) {
+	store := NewMemoryStore()
+	m := NewManager(store, Config{Workers: 1, QueueDepth: 1, Timeout: 0})
+
+	job, err := m.Submit(context.Background(), "analyze", func(ctx context.Context) (any, error) {
+		return "done", ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Close(context.Background())
+
+	if job, _ = store.Get(context.Background(), job.ID); job.Status != StatusSucceeded {
+		t.Errorf("status = %s (%s), want %s", job.Status, job.Error, StatusSucceeded)
+	}
+}
@@ -0,0 +1,133 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	id         TEXT PRIMARY KEY,
+	type       TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	result     BLOB,
+	error      TEXT NOT NULL DEFAULT '',
+	created_at INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS jobs_status_updated ON jobs (status, updated_at);`
+
+// SQLiteStore persists jobs in a SQLite database so they survive restarts
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (or creates) the SQLite database at path
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job database: %w", err)
+	}
+	// SQLite allows a single writer
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create job schema: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Create stores a new job
+func (s *SQLiteStore) Create(ctx context.Context, job *Job) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO jobs (id, type, status, result, error, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, job.Type, string(job.Status), []byte(job.Result), job.Error, job.CreatedAt.UnixMilli(), job.UpdatedAt.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+	return nil
+}
+
+// Get returns a job by ID
+func (s *SQLiteStore) Get(ctx context.Context, id string) (*Job, error) {
+	var job Job
+	var status string
+	var result []byte
+	var created, updated int64
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, type, status, result, error, created_at, updated_at FROM jobs WHERE id = ?`, id).
+		Scan(&job.ID, &job.Type, &status, &result, &job.Error, &created, &updated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load job: %w", err)
+	}
+
+	job.Status = Status(status)
+	if len(result) > 0 {
+		job.Result = result
+	}
+	job.CreatedAt = time.UnixMilli(created).UTC()
+	job.UpdatedAt = time.UnixMilli(updated).UTC()
+	return &job, nil
+}
+
+// Update replaces a stored job
+func (s *SQLiteStore) Update(ctx context.Context, job *Job) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET status = ?, result = ?, error = ?, updated_at = ? WHERE id = ?`,
+		string(job.Status), []byte(job.Result), job.Error, job.UpdatedAt.UnixMilli(), job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes a job
+func (s *SQLiteStore) Delete(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM jobs WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes finished jobs last updated before the given time
+func (s *SQLiteStore) Prune(ctx context.Context, before time.Time) (int, error) {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM jobs WHERE status IN (?, ?, ?) AND updated_at < ?`,
+		string(StatusSucceeded), string(StatusFailed), string(StatusCanceled), before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune jobs: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+// FailUnfinished marks every queued or running job as failed
+func (s *SQLiteStore) FailUnfinished(ctx context.Context, reason string) (int, error) {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET status = ?, error = ?, updated_at = ? WHERE status IN (?, ?)`,
+		string(StatusFailed), reason, time.Now().UnixMilli(), string(StatusQueued), string(StatusRunning))
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail unfinished jobs: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
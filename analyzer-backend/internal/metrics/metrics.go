@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"ea-scanner/internal/models"
+)
+
+// Totals aggregates the usage of a set of requests
+type Totals struct {
+	Requests         int     `json:"requests"`           // Successful requests
+	Errors           int     `json:"errors"`             // Failed requests
+	CacheHits        int     `json:"cache_hits"`         // Requests served from the cache
+	PromptTokens     int     `json:"prompt_tokens"`      // Input tokens
+	CompletionTokens int     `json:"completion_tokens"`  // Output tokens
+	TotalTokens      int     `json:"total_tokens"`       // As reported by the provider
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"` // Sum of per-request estimates
+	AvgLatencyMS     int64   `json:"avg_latency_ms"`     // Mean latency of successful requests
+
+	latencyMS int64
+}
+
+func (t *Totals) add(usage *models.Usage, cacheHit bool) {
+	t.Requests++
+	if cacheHit {
+		t.CacheHits++
+	}
+	t.PromptTokens += usage.PromptTokens
+	t.CompletionTokens += usage.CompletionTokens
+	t.TotalTokens += usage.TotalTokens
+	t.EstimatedCostUSD = math.Round((t.EstimatedCostUSD+usage.EstimatedCostUSD)*1e6) / 1e6
+	t.latencyMS += usage.LatencyMS
+	t.AvgLatencyMS = t.latencyMS / int64(t.Requests)
+}
+
+// Snapshot is a point-in-time copy of the recorded metrics
+type Snapshot struct {
+	Since   time.Time         `json:"since"`    // When recording started
+	Total   Totals            `json:"total"`    // All requests
+	ByKind  map[string]Totals `json:"by_kind"`  // Per request kind, e.g. "analyze"
+	ByModel map[string]Totals `json:"by_model"` // Per model
+}
+
+// Recorder aggregates usage in memory since process start; it is safe for
+// concurrent use
+type Recorder struct {
+	mu      sync.Mutex
+	since   time.Time
+	total   Totals
+	byKind  map[string]*Totals
+	byModel map[string]*Totals
+}
+
+// NewRecorder creates an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		since:   time.Now().UTC(),
+		byKind:  map[string]*Totals{},
+		byModel: map[string]*Totals{},
+	}
+}
+
+// Record adds a successful request of the given kind
+func (r *Recorder) Record(kind string, usage *models.Usage, cacheHit bool) {
+	if usage == nil {
+		usage = &models.Usage{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.total.add(usage, cacheHit)
+	totalsFor(r.byKind, kind).add(usage, cacheHit)
+	if usage.Model != "" {
+		totalsFor(r.byModel, usage.Model).add(usage, cacheHit)
+	}
+}
+
+// RecordError adds a failed request of the given kind
+func (r *Recorder) RecordError(kind string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.total.Errors++
+	totalsFor(r.byKind, kind).Errors++
+}
+
+// Snapshot returns a copy of the current metrics
+func (r *Recorder) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snap := Snapshot{
+		Since:   r.since,
+		Total:   r.total,
+		ByKind:  make(map[string]Totals, len(r.byKind)),
+		ByModel: make(map[string]Totals, len(r.byModel)),
+	}
+	for k, t := range r.byKind {
+		snap.ByKind[k] = *t
+	}
+	for m, t := range r.byModel {
+		snap.ByModel[m] = *t
+	}
+	return snap
+}
+
+func totalsFor(m map[string]*Totals, key string) *Totals {
+	t, ok := m[key]
+	if !ok {
+		t = &Totals{}
+		m[key] = t
+	}
+	return t
+}
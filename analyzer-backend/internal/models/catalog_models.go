@@ -0,0 +1,17 @@
+package models
+
+// ModelInfo describes a model clients may request
+type ModelInfo struct {
+	Name          string  `json:"name"`                     // Model name to send as "model"
+	ContextTokens int     `json:"context_tokens,omitempty"` // Input token limit; omitted when unknown
+	InputPrice    float64 `json:"input_price"`              // USD per million input tokens
+	OutputPrice   float64 `json:"output_price"`             // USD per million output tokens
+	RelativeCost  float64 `json:"relative_cost,omitempty"`  // Blended price relative to the cheapest allowed model; omitted when unpriced
+}
+
+// ModelsResponse lists the models clients may request
+type ModelsResponse struct {
+	Provider string            `json:"provider"` // Provider serving the models
+	Defaults map[string]string `json:"defaults"` // Default model by job type
+	Models   []ModelInfo       `json:"models"`
+}
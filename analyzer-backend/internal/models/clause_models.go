@@ -0,0 +1,23 @@
+package models
+
+// Clause kinds
+const (
+	ClausePreamble = "preamble" // Text before the first heading or numbered clause
+	ClauseSection  = "section"  // Heading that groups the clauses below it
+	ClauseClause   = "clause"   // Numbered or lettered clause
+	ClauseSchedule = "schedule" // Schedule, annexure, appendix or exhibit
+)
+
+// Clause is a node of an agreement's clause tree
+type Clause struct {
+	ID          string    `json:"id"`                   // Stable address, e.g. "3", "3.2", "3.2.a" or "schedule-1"
+	Kind        string    `json:"kind"`                 // preamble/section/clause/schedule
+	Label       string    `json:"label,omitempty"`      // Number as written, e.g. "3.2" or "(a)"
+	Title       string    `json:"title,omitempty"`      // Heading text, if any
+	Body        string    `json:"body,omitempty"`       // Text of the clause itself, without its children
+	StartPage   int       `json:"start_page,omitempty"` // 1-based first page, if the document has pages
+	EndPage     int       `json:"end_page,omitempty"`   // 1-based last page, including children
+	StartOffset int       `json:"start_offset"`         // Character offset of the clause in the document text
+	EndOffset   int       `json:"end_offset"`           // Character offset just past the clause and its children
+	Children    []*Clause `json:"children,omitempty"`   // Sub-clauses in document order
+}
@@ -0,0 +1,52 @@
+package models
+
+// Clause change types
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// CompareRequest compares two versions of an agreement
+type CompareRequest struct {
+	APIKey        string        `json:"api_key"`                  // Client's Gemini API key
+	Original      DocumentInput `json:"original"`                 // Earlier version
+	Revised       DocumentInput `json:"revised"`                  // Later version
+	Model         string        `json:"model,omitempty"`          // Model to analyze with; empty selects the default
+	Jurisdiction  string        `json:"jurisdiction,omitempty"`   // Jurisdiction code for extra rules
+	SkipRedaction bool          `json:"skip_redaction,omitempty"` // Send the documents to the LLM without redacting personal data
+	NoCache       bool          `json:"no_cache,omitempty"`       // Analyze again even if cached results exist
+}
+
+// DocumentInput is one uploaded document
+type DocumentInput struct {
+	Document string `json:"document"` // Base64 encoded document
+	Filename string `json:"filename"` // Original filename with extension
+}
+
+// CompareResult reports how two versions of an agreement differ
+type CompareResult struct {
+	Original           *AnalysisResult  `json:"original"`            // Analysis of the earlier version
+	Revised            *AnalysisResult  `json:"revised"`             // Analysis of the later version
+	Changes            []ClauseChange   `json:"changes"`             // Added, removed and modified clauses
+	FixedFindings      []Finding        `json:"fixed_findings"`      // Issues in the original that the revision resolved
+	IntroducedFindings []Finding        `json:"introduced_findings"` // Issues that first appear in the revision
+	SeverityChanges    []SeverityChange `json:"severity_changes"`    // Issues in both versions whose severity changed
+	RiskScoreDelta     int              `json:"risk_score_delta"`    // Revised minus original risk score
+}
+
+// ClauseChange is a single clause-level difference
+type ClauseChange struct {
+	Type       string  `json:"type"`                 // added/removed/modified
+	ClauseID   string  `json:"clause_id,omitempty"`  // ID of the clause in the revision, or in the original if removed
+	Label      string  `json:"label,omitempty"`      // Clause number or heading, if any
+	Original   string  `json:"original,omitempty"`   // Clause text in the original
+	Revised    string  `json:"revised,omitempty"`    // Clause text in the revision
+	Similarity float64 `json:"similarity,omitempty"` // 0-1 similarity for modified clauses
+}
+
+// SeverityChange is an issue found in both versions with a different severity
+type SeverityChange struct {
+	Original Finding `json:"original"` // Finding in the original
+	Revised  Finding `json:"revised"`  // Matching finding in the revision
+}
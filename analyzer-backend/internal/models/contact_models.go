@@ -0,0 +1,26 @@
+package models
+
+// Contact kinds
+const (
+	ContactURL    = "url"
+	ContactEmail  = "email"
+	ContactPhone  = "phone"
+	ContactDomain = "domain"
+)
+
+// Contact reputation flags
+const (
+	FlagLookalike = "lookalike"  // Imitates a known employer's domain
+	FlagFreemail  = "freemail"   // Free email provider
+	FlagShortener = "shortener"  // URL shortening service
+	FlagIPLiteral = "ip_literal" // Links to an IP address instead of a domain
+)
+
+// Contact is a URL, email address, phone number or domain found in a document
+type Contact struct {
+	Kind   string   `json:"kind"`             // url/email/phone/domain
+	Value  string   `json:"value"`            // As written in the document, or the hyperlink target
+	Domain string   `json:"domain,omitempty"` // Lowercased host of a URL, email address or domain
+	Linked bool     `json:"linked,omitempty"` // Only a hyperlink target, not visible in the text
+	Flags  []string `json:"flags,omitempty"`  // Reputation flags set by the offline checks
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// EmailHeaders are the headers of an uploaded email used for sender checks
+type EmailHeaders struct {
+	From                  string     `json:"from,omitempty"`                   // Sender as shown to the reader
+	ReplyTo               string     `json:"reply_to,omitempty"`               // Where replies go, if it differs from From
+	ReturnPath            string     `json:"return_path,omitempty"`            // Envelope sender that receives bounces
+	Subject               string     `json:"subject,omitempty"`                // Decoded subject line
+	Date                  *time.Time `json:"date,omitempty"`                   // When the email was sent, per its Date header
+	AuthenticationResults []string   `json:"authentication_results,omitempty"` // SPF/DKIM/DMARC verdicts, most recent first
+}
@@ -0,0 +1,15 @@
+package models
+
+import "encoding/json"
+
+// Job types accepted by POST /api/jobs
+const (
+	JobTypeAnalyze       = "analyze"
+	JobTypeResumeAnalyze = "resume_analyze"
+)
+
+// JobRequest submits an analysis to run asynchronously
+type JobRequest struct {
+	Type    string          `json:"type"`    // "analyze" or "resume_analyze"
+	Request json.RawMessage `json:"request"` // AnalyzeRequest or ResumeAnalyzeRequest
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// DocumentMetadata is the authoring information a PDF or DOCX file records
+// about itself, used for forensic checks
+type DocumentMetadata struct {
+	Source         string     `json:"source,omitempty"`           // Email attachment the metadata was read from; empty for the uploaded file
+	Title          string     `json:"title,omitempty"`            // Title set in the document properties
+	Author         string     `json:"author,omitempty"`           // Author set in the document properties
+	LastModifiedBy string     `json:"last_modified_by,omitempty"` // Account that last saved a DOCX file
+	Company        string     `json:"company,omitempty"`          // Organization recorded by the office application
+	Creator        string     `json:"creator,omitempty"`          // Application the document was written in, e.g. Microsoft Word
+	Producer       string     `json:"producer,omitempty"`         // Software that produced the PDF file
+	Created        *time.Time `json:"created,omitempty"`          // Creation date
+	Modified       *time.Time `json:"modified,omitempty"`         // Last modification date
+}
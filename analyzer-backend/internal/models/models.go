@@ -2,28 +2,85 @@ package models
 
 // AnalyzeRequest represents the incoming request from frontend
 type AnalyzeRequest struct {
-	APIKey   string `json:"api_key"`  // Client's Gemini API key
-	Document string `json:"document"` // Base64 encoded document
-	Filename string `json:"filename"` // Original filename with extension
+	APIKey        string `json:"api_key"`                  // Client's Gemini API key
+	Document      string `json:"document"`                 // Base64 encoded document
+	Filename      string `json:"filename"`                 // Original filename with extension
+	Model         string `json:"model,omitempty"`          // Model to analyze with; empty selects the default
+	Jurisdiction  string `json:"jurisdiction,omitempty"`   // Jurisdiction code for extra rules, e.g. "IN", "US-CA"
+	SkipRedaction bool   `json:"skip_redaction,omitempty"` // Send the document to the LLM without redacting personal data
+	NoCache       bool   `json:"no_cache,omitempty"`       // Analyze again even if a cached result exists
 }
 
 // AnalysisResult represents the analysis output
 type AnalysisResult struct {
-	RiskScore       int       `json:"risk_score"`       // 0-100 risk score
-	RiskLevel       string    `json:"risk_level"`       // LOW/MEDIUM/HIGH/CRITICAL
-	ScamIndicators  []Finding `json:"scam_indicators"`  // Potential scam signs
-	RiskyClauses    []Finding `json:"risky_clauses"`    // Problematic contract terms
-	MissingElements []string  `json:"missing_elements"` // Expected elements not found
-	Recommendations []string  `json:"recommendations"`  // Actionable advice
-	Summary         string    `json:"summary"`          // Brief overall assessment
+	RiskScore       int              `json:"risk_score" schema:"min=0,max=100"`                 // 0-100 risk score
+	RiskLevel       string           `json:"risk_level" schema:"enum=LOW|MEDIUM|HIGH|CRITICAL"` // LOW/MEDIUM/HIGH/CRITICAL
+	ScamIndicators  []Finding        `json:"scam_indicators"`                                   // Potential scam signs
+	RiskyClauses    []Finding        `json:"risky_clauses"`                                     // Problematic contract terms
+	MissingElements []string         `json:"missing_elements"`                                  // Expected elements not found
+	Recommendations []string         `json:"recommendations"`                                   // Actionable advice
+	Summary         string           `json:"summary"`                                           // Brief overall assessment
+	Terms           *ContractTerms   `json:"contract_terms,omitempty"`                          // Key terms extracted from the agreement
+	Attempts        int              `json:"attempts" schema:"-"`                               // Model calls needed to get valid output
+	ChunkCount      int              `json:"chunk_count" schema:"-"`                            // Number of parts the document was analyzed in
+	Partial         bool             `json:"partial,omitempty" schema:"-"`                      // Model analysis of some parts, or all of it, failed; Warnings say which
+	Warnings        []string         `json:"warnings,omitempty" schema:"-"`                     // Non-fatal problems during analysis
+	Redaction       *RedactionReport `json:"redaction,omitempty" schema:"-"`                    // Personal data withheld from the LLM
+	Usage           *Usage           `json:"usage,omitempty" schema:"-"`                        // Tokens, latency and estimated cost
+	Document        *DocumentInfo    `json:"document,omitempty" schema:"-"`                     // The uploaded document as parsed
+	Contacts        []Contact        `json:"contacts,omitempty" schema:"-"`                     // URLs, email addresses, phone numbers and domains with reputation flags
+	CacheStatus     string           `json:"-"`                                                 // hit/miss/not_stored/bypass, reported in the Cache-Status header; empty without a cache
+}
+
+// RedactionReport describes the personal data replaced before analysis
+type RedactionReport struct {
+	Enabled bool           `json:"enabled"` // Whether the document was redacted
+	Total   int            `json:"total"`   // Occurrences replaced
+	Counts  map[string]int `json:"counts"`  // Distinct values replaced per kind, e.g. "PHONE": 2
+}
+
+// DocumentInfo describes an uploaded document as parsed
+type DocumentInfo struct {
+	Filename       string             `json:"filename"`                  // Filename as uploaded
+	Format         string             `json:"format"`                    // Detected from the content, e.g. pdf, docx, odt, rtf, html, markdown, email or text
+	Email          *EmailHeaders      `json:"email,omitempty"`           // Headers of an uploaded email
+	Attachments    []string           `json:"attachments,omitempty"`     // Attachments whose text was analyzed with the document
+	Clauses        []*Clause          `json:"clauses,omitempty"`         // Sections, clauses and schedules of an agreement
+	TrackedChanges []TrackedChange    `json:"tracked_changes,omitempty"` // Unresolved insertions and deletions in a DOCX file
+	Pages          int                `json:"pages,omitempty"`           // Page count of a PDF
+	EmptyPages     []int              `json:"empty_pages,omitempty"`     // 1-based pages without extractable text, such as scanned images, which were not analyzed
+	Metadata       []DocumentMetadata `json:"metadata,omitempty"`        // Properties recorded in the file and its attachments
+}
+
+// Tracked change types
+const (
+	ChangeInsertion = "insertion"
+	ChangeDeletion  = "deletion"
+)
+
+// TrackedChange is an insertion or deletion that has not been accepted or
+// rejected
+type TrackedChange struct {
+	Type   string `json:"type"`             // insertion/deletion
+	Author string `json:"author,omitempty"` // Who made the change
+	Date   string `json:"date,omitempty"`   // When, as recorded in the file
+	Text   string `json:"text"`             // Inserted or deleted text
 }
 
 // Finding represents a specific issue found in the document
 type Finding struct {
-	Category    string `json:"category"`    // e.g., "Non-Compete", "Payment Request"
-	Severity    string `json:"severity"`    // LOW/MEDIUM/HIGH/CRITICAL
-	Description string `json:"description"` // Detailed explanation
-	Quote       string `json:"quote"`       // Extracted text from document
+	Category     string `json:"category"`                                        // e.g., "Non-Compete", "Payment Request"
+	Severity     string `json:"severity" schema:"enum=LOW|MEDIUM|HIGH|CRITICAL"` // LOW/MEDIUM/HIGH/CRITICAL
+	Description  string `json:"description"`                                     // Detailed explanation
+	Quote        string `json:"quote"`                                           // Extracted text from document
+	StartOffset  int    `json:"start_offset" schema:"-"`                         // Character offset of the quote in the document text
+	EndOffset    int    `json:"end_offset" schema:"-"`                           // Character offset just past the quote
+	Page         int    `json:"page,omitempty" schema:"-"`                       // 1-based page of the quote, if known
+	ClauseID     string `json:"clause_id,omitempty" schema:"-"`                  // ID of the innermost clause containing the quote, if known
+	Verified     bool   `json:"verified" schema:"-"`                             // Quote was found in the document
+	Source       string `json:"source,omitempty" schema:"-"`                     // "llm", "rule" or "check"
+	RuleID       string `json:"rule_id,omitempty" schema:"optional"`             // ID of the rule that produced or was cited for the finding
+	Jurisdiction string `json:"jurisdiction,omitempty" schema:"-"`               // Jurisdiction of that rule, if any
 }
 
 // ErrorResponse for API errors
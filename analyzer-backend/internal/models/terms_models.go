@@ -0,0 +1,36 @@
+package models
+
+// ContractTerms holds the key terms extracted from an agreement. Terms the
+// document does not state are omitted.
+type ContractTerms struct {
+	EmployerName    string      `json:"employer_name,omitempty" schema:"optional"` // Legal name of the employer
+	Role            string      `json:"role,omitempty" schema:"optional"`          // Job title
+	StartDate       string      `json:"start_date,omitempty" schema:"optional"`    // YYYY-MM-DD when a full date is given, otherwise as written
+	BaseSalary      *Money      `json:"base_salary,omitempty"`                     // Base pay before bonuses
+	Bonus           string      `json:"bonus,omitempty" schema:"optional"`         // Bonus or commission terms
+	NoticePeriod    *Duration   `json:"notice_period,omitempty"`                   // Notice the employee must give
+	ProbationPeriod *Duration   `json:"probation_period,omitempty"`                // Length of probation
+	NonCompete      *NonCompete `json:"non_compete,omitempty"`                     // Post-employment non-compete restriction
+	WorkLocation    string      `json:"work_location,omitempty" schema:"optional"` // Office address, city or "Remote"
+	HoursPerWeek    *float64    `json:"hours_per_week,omitempty" schema:"min=0"`   // Contracted weekly hours
+	GoverningLaw    string      `json:"governing_law,omitempty" schema:"optional"` // Jurisdiction whose law governs the agreement
+}
+
+// Money is an amount of pay over a period
+type Money struct {
+	Amount   float64 `json:"amount" schema:"min=0"`                         // Amount per period
+	Currency string  `json:"currency"`                                      // ISO 4217 code, e.g. "USD"
+	Period   string  `json:"period" schema:"enum=HOUR|DAY|WEEK|MONTH|YEAR"` // HOUR/DAY/WEEK/MONTH/YEAR
+}
+
+// Duration is a length of time as stated in the agreement
+type Duration struct {
+	Value float64 `json:"value" schema:"min=0"`                   // Number of units
+	Unit  string  `json:"unit" schema:"enum=DAY|WEEK|MONTH|YEAR"` // DAY/WEEK/MONTH/YEAR
+}
+
+// NonCompete describes a non-compete restriction
+type NonCompete struct {
+	Duration  *Duration `json:"duration,omitempty"`                    // How long the restriction lasts after employment
+	Geography string    `json:"geography,omitempty" schema:"optional"` // Area it applies to, e.g. "Worldwide"
+}
@@ -0,0 +1,11 @@
+package models
+
+// Usage reports the model usage and estimated cost of a request
+type Usage struct {
+	Model            string  `json:"model"`              // Model that served the request
+	PromptTokens     int     `json:"prompt_tokens"`      // Input tokens across all model calls
+	CompletionTokens int     `json:"completion_tokens"`  // Output tokens across all model calls
+	TotalTokens      int     `json:"total_tokens"`       // As reported by the provider
+	LatencyMS        int64   `json:"latency_ms"`         // Wall-clock time to produce the result
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"` // From the configured price table; 0 for unpriced models
+}
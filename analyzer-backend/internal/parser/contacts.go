@@ -0,0 +1,180 @@
+package parser
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"ea-scanner/internal/models"
+	"ea-scanner/internal/redact"
+)
+
+var (
+	urlPattern   = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"'\[\]{}|\\^` + "`" + `]+`)
+	emailPattern = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9](?:[a-z0-9-]*[a-z0-9])?(?:\.[a-z0-9](?:[a-z0-9-]*[a-z0-9])?)*\.[a-z]{2,}\b`)
+
+	// Bare domains are only recognized under common top-level domains, so
+	// that abbreviations and clause numbers are not mistaken for them
+	domainPattern = regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+(?:com|net|org|edu|gov|io|co|in|uk|us|biz|info|me|xyz|top|online|site|live|app|dev|ai|jobs|careers|work|club|shop|store|ru|cn|de|fr|nl|au|ca|sg|ae|ng|pk|ph|my|za|ke)\b`)
+)
+
+// contactMatch is a contact found in the text at byte offset pos
+type contactMatch struct {
+	pos     int
+	contact models.Contact
+}
+
+// ExtractContacts collects the URLs, email addresses, phone numbers and bare
+// domains in the text, followed by hyperlink targets that do not appear in
+// it. Each is listed once, in order of appearance.
+func ExtractContacts(text string, links []string) []models.Contact {
+	var contacts []models.Contact
+	seen := map[string]bool{}
+	add := func(c models.Contact) {
+		key := c.Kind + "\x00" + strings.ToLower(c.Value)
+		if c.Kind == models.ContactPhone {
+			key = c.Kind + "\x00" + phoneDigits(c.Value)
+		}
+		if !seen[key] {
+			seen[key] = true
+			contacts = append(contacts, c)
+		}
+	}
+
+	// Spans of URLs and email addresses, which contain domains and digits
+	// that are not contacts of their own
+	var taken [][]int
+	overlaps := func(loc []int) bool {
+		for _, t := range taken {
+			if loc[0] < t[1] && t[0] < loc[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var found []contactMatch
+	collect := func(pattern *regexp.Regexp, build func(match string) (models.Contact, bool)) {
+		for _, loc := range pattern.FindAllStringSubmatchIndex(text, -1) {
+			// Patterns with a capture group match only the group
+			if len(loc) >= 4 && loc[2] >= 0 {
+				loc = loc[2:4]
+			}
+			loc = loc[:2]
+			if overlaps(loc) {
+				continue
+			}
+			if c, ok := build(trimContact(text[loc[0]:loc[1]])); ok {
+				taken = append(taken, loc)
+				found = append(found, contactMatch{pos: loc[0], contact: c})
+			}
+		}
+	}
+	collect(urlPattern, urlContact)
+	collect(emailPattern, emailContact)
+	collect(domainPattern, func(match string) (models.Contact, bool) {
+		return models.Contact{Kind: models.ContactDomain, Value: match, Domain: strings.ToLower(match)}, true
+	})
+	for _, pattern := range redact.PhonePatterns {
+		collect(pattern, func(match string) (models.Contact, bool) {
+			return models.Contact{Kind: models.ContactPhone, Value: match}, redact.PhoneValid(match)
+		})
+	}
+
+	slices.SortStableFunc(found, func(a, b contactMatch) int {
+		return a.pos - b.pos
+	})
+	for _, f := range found {
+		add(f.contact)
+	}
+
+	for _, link := range links {
+		if c, ok := linkContact(link); ok {
+			c.Linked = true
+			add(c)
+		}
+	}
+	return contacts
+}
+
+// linkTarget returns a hyperlink target if it leads outside the document:
+// a web address, an email address or a phone number
+func linkTarget(href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	lower := strings.ToLower(href)
+	for _, scheme := range []string{"http://", "https://", "mailto:", "tel:"} {
+		if strings.HasPrefix(lower, scheme) && len(href) > len(scheme) {
+			return href, true
+		}
+	}
+	return "", false
+}
+
+// linkContact converts a hyperlink target to a contact
+func linkContact(link string) (models.Contact, bool) {
+	lower := strings.ToLower(link)
+	switch {
+	case strings.HasPrefix(lower, "mailto:"):
+		addr, _, _ := strings.Cut(link[len("mailto:"):], "?")
+		if unescaped, err := url.PathUnescape(addr); err == nil {
+			addr = unescaped
+		}
+		// A mailto link can list several recipients; the first is kept
+		addr, _, _ = strings.Cut(addr, ",")
+		return emailContact(strings.TrimSpace(addr))
+	case strings.HasPrefix(lower, "tel:"):
+		number := strings.TrimSpace(link[len("tel:"):])
+		return models.Contact{Kind: models.ContactPhone, Value: number}, phoneDigits(number) != ""
+	}
+	return urlContact(link)
+}
+
+// urlContact builds a URL contact, which must have a host
+func urlContact(value string) (models.Contact, bool) {
+	raw := value
+	if strings.HasPrefix(strings.ToLower(raw), "www.") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return models.Contact{}, false
+	}
+	return models.Contact{Kind: models.ContactURL, Value: value, Domain: strings.ToLower(u.Hostname())}, true
+}
+
+// emailContact builds an email address contact
+func emailContact(value string) (models.Contact, bool) {
+	at := strings.LastIndex(value, "@")
+	if at <= 0 || at == len(value)-1 {
+		return models.Contact{}, false
+	}
+	return models.Contact{Kind: models.ContactEmail, Value: value, Domain: strings.ToLower(value[at+1:])}, true
+}
+
+// trimContact removes punctuation that ends the sentence rather than the
+// contact, and a closing parenthesis without an opening one
+func trimContact(s string) string {
+	for {
+		trimmed := strings.TrimRight(s, ".,;:!?'\"")
+		if strings.HasSuffix(trimmed, ")") && !strings.Contains(trimmed, "(") {
+			trimmed = trimmed[:len(trimmed)-1]
+		}
+		if trimmed == s {
+			return s
+		}
+		s = trimmed
+	}
+}
+
+// phoneDigits returns the digits of a phone number, with a leading + kept
+func phoneDigits(s string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(s) {
+		if unicode.IsDigit(r) || (i == 0 && r == '+') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
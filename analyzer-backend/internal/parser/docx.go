@@ -0,0 +1,354 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"ea-scanner/internal/models"
+)
+
+const (
+	wordNS   = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	markupNS = "http://schemas.openxmlformats.org/markup-compatibility/2006"
+)
+
+// parseDocx extracts text from a DOCX file by walking its WordprocessingML
+// parts: headers, the document body, footnotes, endnotes and footers, in that
+// order. Table rows end with a newline and cells are separated by tabs, and
+// page breaks become form feeds. The text reads as if tracked changes were
+// accepted; the changes themselves are returned in TrackedChanges, and the
+// document properties and hyperlink targets in Metadata and Links.
+func parseDocx(data []byte) (*Document, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DOCX: %w", err)
+	}
+
+	parts := map[string]*zip.File{}
+	for _, f := range archive.File {
+		parts[f.Name] = f
+	}
+	if parts["word/document.xml"] == nil {
+		return nil, fmt.Errorf("failed to parse DOCX: word/document.xml not found")
+	}
+
+	w := &docxWriter{}
+	var sections []string
+	addPart := func(name string) error {
+		text, err := w.part(parts[name])
+		if err != nil {
+			return fmt.Errorf("failed to parse DOCX %s: %w", path.Base(name), err)
+		}
+		// First-page and even-page headers often repeat the default one
+		if text = strings.TrimSpace(text); text != "" && !slices.Contains(sections, text) {
+			sections = append(sections, text)
+		}
+		return nil
+	}
+
+	headers, footers := docxParts(parts, "header"), docxParts(parts, "footer")
+	for _, name := range headers {
+		if err := addPart(name); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range []string{"word/document.xml", "word/footnotes.xml", "word/endnotes.xml"} {
+		if parts[name] == nil {
+			continue
+		}
+		if err := addPart(name); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range footers {
+		if err := addPart(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Document{
+		Text:           strings.Join(sections, "\n\n"),
+		TrackedChanges: w.changes,
+		Metadata:       metadataList(docxMetadata(parts)),
+		Links:          docxLinks(parts),
+	}, nil
+}
+
+// docxLinks returns the external hyperlink targets in the relationships of
+// the document, header, footer and note parts
+func docxLinks(parts map[string]*zip.File) []string {
+	var names []string
+	for name := range parts {
+		if strings.HasPrefix(name, "word/_rels/") && strings.HasSuffix(name, ".rels") {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var links []string
+	for _, name := range names {
+		rc, err := openZipFile(parts[name])
+		if err != nil {
+			continue
+		}
+		dec := xml.NewDecoder(rc)
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				break
+			}
+			t, ok := tok.(xml.StartElement)
+			if !ok || t.Name.Local != "Relationship" || !strings.HasSuffix(docxAttr(t, "Type"), "/hyperlink") {
+				continue
+			}
+			if target, ok := linkTarget(docxAttr(t, "Target")); ok {
+				links = append(links, target)
+			}
+		}
+		rc.Close()
+	}
+	return links
+}
+
+// docxMetadata reads the core and application properties. Parts that are
+// missing or cannot be read leave their properties empty.
+func docxMetadata(parts map[string]*zip.File) models.DocumentMetadata {
+	fields := func(name string) map[string]string {
+		f := parts[name]
+		if f == nil {
+			return nil
+		}
+		rc, err := openZipFile(f)
+		if err != nil {
+			return nil
+		}
+		defer rc.Close()
+		return xmlFields(rc)
+	}
+	core, app := fields("docProps/core.xml"), fields("docProps/app.xml")
+
+	meta := models.DocumentMetadata{
+		Title:          core["title"],
+		Author:         core["creator"],
+		LastModifiedBy: core["lastModifiedBy"],
+		Company:        app["Company"],
+		Creator:        app["Application"],
+		Created:        metadataDate(core["created"]),
+		Modified:       metadataDate(core["modified"]),
+	}
+	if meta.Creator != "" && app["AppVersion"] != "" {
+		meta.Creator += " " + app["AppVersion"]
+	}
+	return meta
+}
+
+// docxParts returns the names of header or footer parts in numeric order
+func docxParts(parts map[string]*zip.File, kind string) []string {
+	var names []string
+	for name := range parts {
+		if strings.HasPrefix(name, "word/"+kind) && strings.HasSuffix(name, ".xml") && !strings.Contains(name[len("word/"):], "/") {
+			names = append(names, name)
+		}
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if len(a) != len(b) {
+			// header2.xml before header10.xml
+			return len(a) - len(b)
+		}
+		return strings.Compare(a, b)
+	})
+	return names
+}
+
+// docxWriter extracts the text of WordprocessingML parts and collects their
+// tracked changes
+type docxWriter struct {
+	buf     strings.Builder
+	changes []models.TrackedChange
+}
+
+// docxChange is a tracked change being read
+type docxChange struct {
+	change models.TrackedChange
+	depth  int  // Element depth of the w:ins or w:del
+	merged bool // Continues the previous change
+}
+
+// part returns the text of one part
+func (w *docxWriter) part(f *zip.File) (string, error) {
+	rc, err := openZipFile(f)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	w.buf.Reset()
+	dec := xml.NewDecoder(rc)
+	depth := 0
+	skip := 0       // Depth inside elements whose text is not part of the document
+	cells := 0      // Depth inside table cells, where paragraphs are joined on one line
+	inText := false // Inside w:t or w:delText
+	// Open tracked changes, innermost last: a w:del may sit inside another
+	// author's w:ins, and its text is then deleted, not inserted
+	var open []*docxChange
+	lastChangeEnd := -1 // Text length after the last change, to merge adjacent ones
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if skip > 0 || docxSkipped(t) {
+				skip++
+				continue
+			}
+			if t.Name.Space != wordNS {
+				continue
+			}
+			switch t.Name.Local {
+			case "t", "delText":
+				inText = true
+			case "tab", "ptab":
+				w.buf.WriteString("\t")
+			case "br", "cr":
+				if docxAttr(t, "type") == "page" {
+					w.pageBreak()
+				} else {
+					w.buf.WriteString("\n")
+				}
+			case "lastRenderedPageBreak":
+				// Where Word last laid out a page; the best page number available
+				w.pageBreak()
+			case "noBreakHyphen":
+				w.buf.WriteString("-")
+			case "tc":
+				cells++
+			case "footnoteReference", "endnoteReference":
+				fmt.Fprintf(&w.buf, "[%s]", docxAttr(t, "id"))
+			case "footnote", "endnote":
+				// Separator notes are skipped, so this is a real note
+				fmt.Fprintf(&w.buf, "[%s] ", docxAttr(t, "id"))
+			case "ins", "del", "moveTo", "moveFrom":
+				kind := models.ChangeInsertion
+				if t.Name.Local == "del" || t.Name.Local == "moveFrom" {
+					kind = models.ChangeDeletion
+				}
+				change := &docxChange{
+					change: models.TrackedChange{Type: kind, Author: docxAttr(t, "author"), Date: docxAttr(t, "date")},
+					depth:  depth,
+				}
+				if n := len(w.changes); n > 0 && lastChangeEnd == w.buf.Len() {
+					prev := w.changes[n-1]
+					change.merged = prev.Type == kind && prev.Author == change.change.Author
+				}
+				open = append(open, change)
+			}
+		case xml.EndElement:
+			depth--
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if n := len(open); n > 0 && depth == open[n-1].depth-1 {
+				change := open[n-1]
+				open = open[:n-1]
+				if text := strings.TrimSpace(change.change.Text); text != "" {
+					if change.merged {
+						w.changes[len(w.changes)-1].Text += change.change.Text
+					} else {
+						w.changes = append(w.changes, change.change)
+					}
+					lastChangeEnd = w.buf.Len()
+				}
+				continue
+			}
+			if t.Name.Space != wordNS {
+				continue
+			}
+			switch t.Name.Local {
+			case "t", "delText":
+				inText = false
+			case "p":
+				if cells > 0 {
+					w.buf.WriteString(" ")
+				} else {
+					w.buf.WriteString("\n")
+				}
+			case "tc":
+				cells--
+				w.buf.WriteString("\t")
+			case "tr":
+				w.buf.WriteString("\n")
+			case "footnote", "endnote":
+				w.buf.WriteString("\n")
+			}
+		case xml.CharData:
+			if skip > 0 || !inText {
+				continue
+			}
+			if n := len(open); n > 0 {
+				// Text belongs to the innermost change, and is reported but not
+				// shown if any enclosing change deleted it
+				open[n-1].change.Text += string(t)
+				if slices.ContainsFunc(open, func(c *docxChange) bool { return c.change.Type == models.ChangeDeletion }) {
+					continue
+				}
+			}
+			w.buf.Write(t)
+		}
+	}
+
+	return w.buf.String(), nil
+}
+
+// pageBreak ends the page unless nothing has been written since the last
+// break, as when a hard break is followed by a rendered one
+func (w *docxWriter) pageBreak() {
+	s := w.buf.String()
+	if strings.TrimRight(s, " \t\n") == strings.TrimRight(s, " \t\n\f") && strings.TrimSpace(s) != "" {
+		w.buf.WriteString("\f")
+	}
+}
+
+// docxSkipped reports whether an element's content is left out of the text:
+// field instructions, the fallback copy of alternate content and separator
+// footnotes
+func docxSkipped(t xml.StartElement) bool {
+	switch {
+	case t.Name.Space == markupNS && t.Name.Local == "Fallback":
+		return true
+	case t.Name.Space != wordNS:
+		return false
+	}
+	switch t.Name.Local {
+	case "instrText", "delInstrText", "rPr", "pPr", "sectPr", "tblPr", "trPr", "tcPr":
+		// Properties hold tracked formatting changes, not text
+		return true
+	case "footnote", "endnote":
+		return docxAttr(t, "type") != ""
+	}
+	return false
+}
+
+// docxAttr returns the value of a WordprocessingML attribute
+func docxAttr(t xml.StartElement, local string) string {
+	for _, attr := range t.Attr {
+		if attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"ea-scanner/internal/models"
+)
+
+// docxBody wraps WordprocessingML body content in a document part
+func docxBody(body string) [2]string {
+	return [2]string{"word/document.xml", `<w:document xmlns:w="` + wordNS + `"><w:body>` + body + `</w:body></w:document>`}
+}
+
+func TestParseDocxTrackedChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		text string
+		want []models.TrackedChange
+	}{
+		{
+			name: "insertion and deletion",
+			body: `<w:p><w:r><w:t xml:space="preserve">Notice is </w:t></w:r>` +
+				`<w:del w:author="Bob"><w:r><w:delText>30</w:delText></w:r></w:del>` +
+				`<w:ins w:author="Bob"><w:r><w:t>90</w:t></w:r></w:ins>` +
+				`<w:r><w:t xml:space="preserve"> days.</w:t></w:r></w:p>`,
+			text: "Notice is 90 days.",
+			want: []models.TrackedChange{
+				{Type: models.ChangeDeletion, Author: "Bob", Text: "30"},
+				{Type: models.ChangeInsertion, Author: "Bob", Text: "90"},
+			},
+		},
+		{
+			name: "deletion inside another author's insertion",
+			body: `<w:p><w:r><w:t xml:space="preserve">Pay </w:t></w:r>` +
+				`<w:ins w:author="Alice"><w:r><w:t xml:space="preserve">monthly </w:t></w:r>` +
+				`<w:del w:author="Bob"><w:r><w:delText>plus a training fee</w:delText></w:r></w:del></w:ins>` +
+				`<w:r><w:t>in arrears.</w:t></w:r></w:p>`,
+			text: "Pay monthly in arrears.",
+			want: []models.TrackedChange{
+				{Type: models.ChangeDeletion, Author: "Bob", Text: "plus a training fee"},
+				{Type: models.ChangeInsertion, Author: "Alice", Text: "monthly "},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parseDocx(zipArchive(t, docxBody(tt.body)))
+			if err != nil {
+				t.Fatalf("parseDocx() error = %v", err)
+			}
+			if got := strings.TrimSpace(doc.Text); got != tt.text {
+				t.Errorf("text = %q, want %q", got, tt.text)
+			}
+			if len(doc.TrackedChanges) != len(tt.want) {
+				t.Fatalf("tracked changes = %+v, want %+v", doc.TrackedChanges, tt.want)
+			}
+			for i, want := range tt.want {
+				if got := doc.TrackedChanges[i]; got != want {
+					t.Errorf("tracked change %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseZipPartTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("word/document.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Compresses to well under a megabyte
+	chunk := bytes.Repeat([]byte(" "), 1<<20)
+	for range maxZipPart>>20 + 1 {
+		w.Write(chunk)
+	}
+	zw.Close()
+
+	if _, err := parseDocx(buf.Bytes()); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("parseDocx() error = %v, want a size limit error", err)
+	}
+}
@@ -0,0 +1,317 @@
+package parser
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"ea-scanner/internal/models"
+)
+
+const (
+	maxEmailDepth  = 3  // Attached messages followed inside an email
+	maxMIMENesting = 10 // Multipart levels followed inside one message
+)
+
+// emailHeaderLine matches an RFC 822 header field name
+var emailHeaderLine = regexp.MustCompile(`^([!-9;-~]+):`)
+
+// transportHeaders are added by mail software, so a header block with one of
+// them is a real message rather than text that happens to start with "From:"
+var transportHeaders = map[string]bool{
+	"received": true, "return-path": true, "message-id": true, "mime-version": true,
+	"delivered-to": true, "authentication-results": true, "dkim-signature": true,
+}
+
+// shownHeaders are repeated at the top of the extracted text, as a mail
+// client would show them
+var shownHeaders = []string{"From", "To", "Cc", "Reply-To", "Date", "Subject"}
+
+// looksLikeEmail reports whether text starts with an optional mbox postmark
+// and an RFC 822 header block with a sender and a transport header
+func looksLikeEmail(text string) bool {
+	if strings.HasPrefix(text, "From ") {
+		_, text, _ = strings.Cut(text, "\n")
+	}
+
+	sender, transport := false, false
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || i >= 200 {
+			break
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			// Folded continuation of the previous header
+			if i == 0 {
+				return false
+			}
+			continue
+		}
+		m := emailHeaderLine.FindStringSubmatch(line)
+		if m == nil {
+			return false
+		}
+		name := strings.ToLower(m[1])
+		sender = sender || name == "from"
+		transport = transport || transportHeaders[name]
+	}
+	return sender && transport
+}
+
+// parseEmail extracts an email's headers, body and attachments. The body
+// prefers text/plain over HTML, and attachments in supported formats are
+// parsed and appended to the text.
+func parseEmail(data []byte) (*Document, error) {
+	data, err := mboxMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	return parseMessage(data, 0)
+}
+
+// mboxMessage returns the message in a single-message mbox file, removing the
+// postmark line and ">From " quoting. Other input is returned unchanged.
+func mboxMessage(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte("From ")) {
+		return data, nil
+	}
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	var msg bytes.Buffer
+	blank := false
+	for _, line := range lines[1:] {
+		if blank && bytes.HasPrefix(line, []byte("From ")) {
+			return nil, fmt.Errorf("failed to parse email: mbox file contains more than one message; upload a single message")
+		}
+		if unquoted := bytes.TrimLeft(line, ">"); len(unquoted) < len(line) && bytes.HasPrefix(unquoted, []byte("From ")) {
+			line = line[1:]
+		}
+		blank = len(bytes.TrimRight(line, "\r\n")) == 0
+		msg.Write(line)
+	}
+	return msg.Bytes(), nil
+}
+
+// parseMessage parses an RFC 822 message; depth counts enclosing messages
+func parseMessage(data []byte, depth int) (*Document, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email: %w", err)
+	}
+
+	parts := &mimeParts{depth: depth}
+	if err := parts.walk(textproto.MIMEHeader(msg.Header), msg.Body, 0); err != nil {
+		return nil, fmt.Errorf("failed to parse email: %w", err)
+	}
+
+	var buf strings.Builder
+	for _, name := range shownHeaders {
+		if value := decodeHeader(msg.Header.Get(name)); value != "" {
+			fmt.Fprintf(&buf, "%s: %s\n", name, value)
+		}
+	}
+	buf.WriteString("\n")
+	body, links := parts.body()
+	buf.WriteString(body)
+	for _, text := range parts.attachmentText {
+		buf.WriteString("\n\n")
+		buf.WriteString(text)
+	}
+
+	return &Document{
+		Text:        buf.String(),
+		Email:       emailHeaders(msg.Header),
+		Attachments: parts.attachments,
+		Metadata:    parts.metadata,
+		Links:       append(links, parts.links...),
+	}, nil
+}
+
+// emailHeaders returns the headers used for sender checks
+func emailHeaders(h mail.Header) *models.EmailHeaders {
+	headers := &models.EmailHeaders{
+		From:                  decodeHeader(h.Get("From")),
+		ReplyTo:               decodeHeader(h.Get("Reply-To")),
+		ReturnPath:            h.Get("Return-Path"),
+		Subject:               decodeHeader(h.Get("Subject")),
+		AuthenticationResults: h["Authentication-Results"],
+	}
+	if date, err := h.Date(); err == nil {
+		headers.Date = &date
+	}
+	return headers
+}
+
+// mimeParts collects the text and attachments of a MIME message
+type mimeParts struct {
+	depth          int                       // Enclosing messages, for attached emails
+	plain          []string                  // text/plain body parts
+	html           []string                  // text/html body parts, used when there is no plain text
+	attachments    []string                  // Names of parsed attachments
+	attachmentText []string                  // Text of parsed attachments, with a heading each
+	metadata       []models.DocumentMetadata // Document properties of parsed attachments
+	links          []string                  // Hyperlink targets in parsed attachments
+}
+
+// walk visits a MIME entity and its children
+func (p *mimeParts) walk(header textproto.MIMEHeader, body io.Reader, nesting int) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		// RFC 2045 default
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if nesting >= maxMIMENesting || params["boundary"] == "" {
+			return nil
+		}
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := p.walk(part.Header, part, nesting+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(transferDecoder(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return err
+	}
+
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := decodeHeader(cmp.Or(dispParams["filename"], params["name"]))
+	isText := mediaType == "text/plain" || mediaType == "text/html"
+
+	if filename == "" && mediaType == "message/rfc822" {
+		// Name forwarded messages so they parse as email even without transport headers
+		filename = fmt.Sprintf("message-%d.eml", len(p.attachments)+1)
+	}
+
+	switch {
+	case mediaType == "message/rfc822", disposition == "attachment", filename != "" && !isText:
+		p.attach(filename, data)
+	case mediaType == "text/plain":
+		p.plain = append(p.plain, decodeCharset(data, params["charset"]))
+	case mediaType == "text/html":
+		p.html = append(p.html, decodeCharset(data, params["charset"]))
+	}
+	return nil
+}
+
+// attach parses an attachment with the registered extractors. Attachments in
+// unsupported formats, such as images, are skipped.
+func (p *mimeParts) attach(filename string, data []byte) {
+	if filename == "" {
+		filename = fmt.Sprintf("attachment-%d", len(p.attachments)+1)
+	}
+
+	spec, err := resolve(Sniff(data), strings.ToLower(filepath.Ext(filename)))
+	if err != nil {
+		return
+	}
+	var doc *Document
+	if spec.Format == FormatEmail {
+		if p.depth+1 > maxEmailDepth {
+			return
+		}
+		if data, err = mboxMessage(data); err == nil {
+			doc, err = parseMessage(data, p.depth+1)
+		}
+	} else {
+		doc, err = spec.Extract(data)
+	}
+	if err != nil || strings.TrimSpace(doc.Text) == "" {
+		return
+	}
+
+	p.attachments = append(p.attachments, filename)
+	for _, nested := range doc.Attachments {
+		p.attachments = append(p.attachments, filename+"/"+nested)
+	}
+	p.links = append(p.links, doc.Links...)
+	for _, meta := range doc.Metadata {
+		meta.Source = strings.TrimSuffix(filename+"/"+meta.Source, "/")
+		p.metadata = append(p.metadata, meta)
+	}
+	p.attachmentText = append(p.attachmentText, fmt.Sprintf("--- Attachment: %s ---\n%s", filename, doc.Text))
+}
+
+// body returns the message text, preferring plain text over HTML, and the
+// link targets of the HTML parts. Links are collected even when the plain
+// text is shown, since a reader's mail client displays the HTML.
+func (p *mimeParts) body() (string, []string) {
+	texts := make([]string, 0, len(p.html))
+	var links []string
+	for _, part := range p.html {
+		if doc, err := parseHTML([]byte(part)); err == nil {
+			texts = append(texts, doc.Text)
+			links = append(links, doc.Links...)
+		}
+	}
+	if len(p.plain) > 0 {
+		return strings.Join(p.plain, "\n\n"), links
+	}
+	return strings.Join(texts, "\n\n"), links
+}
+
+// transferDecoder undoes a Content-Transfer-Encoding
+func transferDecoder(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	}
+	return r
+}
+
+// decodeCharset returns text in the given charset as UTF-8. Latin-1 labels
+// are read as Windows-1252, as browsers do; unknown charsets are read as UTF-8.
+func decodeCharset(data []byte, charset string) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "latin-1", "windows-1252", "cp1252":
+		var buf strings.Builder
+		for _, b := range data {
+			buf.WriteRune(decodeCP1252(b))
+		}
+		return buf.String()
+	}
+	return decodeText(data)
+}
+
+// headerDecoder decodes RFC 2047 encoded words in headers
+var headerDecoder = mime.WordDecoder{
+	CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		return strings.NewReader(decodeCharset(data, charset)), nil
+	},
+}
+
+// decodeHeader decodes a header value, returning it unchanged if it is malformed
+func decodeHeader(value string) string {
+	decoded, err := headerDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
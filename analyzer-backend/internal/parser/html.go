@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// htmlSkipped are elements whose content is never visible text
+var htmlSkipped = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Noscript: true,
+	atom.Template: true, atom.Svg: true, atom.Math: true, atom.Iframe: true,
+	atom.Object: true, atom.Select: true, atom.Button: true,
+}
+
+// htmlBlocks are elements that start and end on their own line
+var htmlBlocks = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true,
+	atom.Dd: true, atom.Div: true, atom.Dl: true, atom.Dt: true, atom.Fieldset: true,
+	atom.Figcaption: true, atom.Figure: true, atom.Footer: true, atom.Form: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Header: true, atom.Hr: true, atom.Li: true, atom.Main: true, atom.Nav: true,
+	atom.Ol: true, atom.P: true, atom.Pre: true, atom.Section: true, atom.Table: true,
+	atom.Tr: true, atom.Ul: true, atom.Caption: true, atom.Summary: true, atom.Details: true,
+}
+
+// parseHTML extracts the visible text of an HTML document. Scripts, styles
+// and hidden elements are dropped, block elements keep their own lines and
+// table cells are separated by tabs. Link targets are returned in Links.
+func parseHTML(data []byte) (*Document, error) {
+	doc, err := html.Parse(strings.NewReader(decodeText(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	w := &htmlWriter{}
+	w.walk(doc)
+	return &Document{Text: strings.TrimSpace(w.buf.String()), Links: w.links}, nil
+}
+
+// htmlWriter accumulates visible text, collapsing whitespace outside <pre>,
+// and the targets of visible links
+type htmlWriter struct {
+	buf   strings.Builder
+	pre   int  // Depth inside <pre> elements
+	space bool // A collapsed space is pending before the next word
+	links []string
+}
+
+func (w *htmlWriter) walk(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data)
+		return
+	case html.ElementNode:
+		if htmlSkipped[n.DataAtom] || htmlHidden(n) {
+			return
+		}
+	case html.CommentNode, html.DoctypeNode:
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		w.newline()
+		return
+	case atom.Td, atom.Th:
+		if htmlPrevElement(n) != nil {
+			w.raw("\t")
+		}
+	case atom.A:
+		for _, attr := range n.Attr {
+			if attr.Key != "href" {
+				continue
+			}
+			if target, ok := linkTarget(attr.Val); ok {
+				w.links = append(w.links, target)
+			}
+		}
+	case atom.Pre:
+		w.pre++
+		defer func() { w.pre-- }()
+	}
+
+	block := n.Type == html.ElementNode && htmlBlocks[n.DataAtom]
+	if block {
+		w.newline()
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c)
+	}
+	if block {
+		w.newline()
+	}
+}
+
+// text writes a text node, collapsing whitespace unless inside <pre>
+func (w *htmlWriter) text(s string) {
+	if w.pre > 0 {
+		w.raw(s)
+		return
+	}
+	if s != "" && isHTMLSpace(s[0]) {
+		w.space = true
+	}
+	for i, word := range strings.Fields(s) {
+		if i > 0 || w.space {
+			w.raw(" ")
+		}
+		w.buf.WriteString(word)
+		w.space = false
+	}
+	if s != "" && isHTMLSpace(s[len(s)-1]) {
+		w.space = true
+	}
+}
+
+// raw writes s unchanged, dropping any pending space
+func (w *htmlWriter) raw(s string) {
+	w.space = false
+	if s == " " && w.atLineStart() {
+		return
+	}
+	w.buf.WriteString(s)
+}
+
+// newline ends the current line unless it is already empty
+func (w *htmlWriter) newline() {
+	w.space = false
+	if !w.atLineStart() {
+		w.buf.WriteString("\n")
+	}
+}
+
+func (w *htmlWriter) atLineStart() bool {
+	s := w.buf.String()
+	return s == "" || strings.HasSuffix(s, "\n")
+}
+
+// htmlHidden reports whether an element is hidden from readers
+func htmlHidden(n *html.Node) bool {
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "hidden":
+			return true
+		case "style":
+			style := strings.ReplaceAll(strings.ToLower(attr.Val), " ", "")
+			if strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// htmlPrevElement returns the previous sibling element, skipping text nodes
+func htmlPrevElement(n *html.Node) *html.Node {
+	for p := n.PrevSibling; p != nil; p = p.PrevSibling {
+		if p.Type == html.ElementNode {
+			return p
+		}
+	}
+	return nil
+}
+
+func isHTMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
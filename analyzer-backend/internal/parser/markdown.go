@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	mdHeading      = regexp.MustCompile(`^\s{0,3}#{1,6}\s+`)
+	mdClosingHash  = regexp.MustCompile(`\s+#+\s*$`)
+	mdRule         = regexp.MustCompile(`^\s{0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,}|(?:=\s*){3,})$`)
+	mdFence        = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+	mdQuote        = regexp.MustCompile(`^\s{0,3}(>\s?)+`)
+	mdTableDivider = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	mdImage        = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink         = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	mdAutolink     = regexp.MustCompile(`<((?:https?://|mailto:)[^>\s]+)>`)
+	mdCode         = regexp.MustCompile("`+([^`]+)`+")
+	mdStrong       = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	mdEmphasis     = regexp.MustCompile(`\*(\S(?:.*?\S)?)\*`)
+	mdUnderscore   = regexp.MustCompile(`(^|[^\w])_(\S(?:.*?\S)?)_([^\w]|$)`)
+	mdStrike       = regexp.MustCompile(`~~(.+?)~~`)
+	mdEscape       = regexp.MustCompile("\\\\([\\\\`*_{}\\[\\]()#+\\-.!|>~])")
+)
+
+// parseMarkdown strips Markdown syntax, keeping the text of headings, lists,
+// quotes, tables and code. Links keep their target after the link text so
+// URLs stay visible to the analysis.
+func parseMarkdown(data []byte) (string, error) {
+	lines := strings.Split(strings.ReplaceAll(decodeText(data), "\r\n", "\n"), "\n")
+
+	out := make([]string, 0, len(lines))
+	inFence := false
+	for _, line := range lines {
+		if mdFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+		if mdTableDivider.MatchString(line) && strings.Contains(line, "|") {
+			// Table dividers separate the header row from the body
+			continue
+		}
+		if mdRule.MatchString(line) {
+			// Rules and setext underlines carry no text
+			out = append(out, "")
+			continue
+		}
+
+		if mdHeading.MatchString(line) {
+			line = mdClosingHash.ReplaceAllString(mdHeading.ReplaceAllString(line, ""), "")
+		}
+		line = mdQuote.ReplaceAllString(line, "")
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "|") && strings.HasSuffix(trimmed, "|") {
+			cells := strings.Split(strings.Trim(trimmed, "|"), "|")
+			for i, cell := range cells {
+				cells[i] = strings.TrimSpace(cell)
+			}
+			line = strings.Join(cells, "\t")
+		}
+		out = append(out, mdInline(line))
+	}
+
+	return strings.Join(out, "\n"), nil
+}
+
+// mdInline strips inline Markdown syntax from one line
+func mdInline(line string) string {
+	line = mdImage.ReplaceAllString(line, "$1")
+	line = mdLink.ReplaceAllStringFunc(line, func(m string) string {
+		parts := mdLink.FindStringSubmatch(m)
+		if parts[1] == parts[2] {
+			return parts[1]
+		}
+		return parts[1] + " (" + parts[2] + ")"
+	})
+	line = mdAutolink.ReplaceAllString(line, "$1")
+	line = mdCode.ReplaceAllString(line, "$1")
+	line = mdStrong.ReplaceAllString(line, "$1$2")
+	line = mdEmphasis.ReplaceAllString(line, "$1")
+	line = mdUnderscore.ReplaceAllString(line, "$1$2$3")
+	line = mdStrike.ReplaceAllString(line, "$1")
+	return mdEscape.ReplaceAllString(line, "$1")
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+
+	"ea-scanner/internal/models"
+)
+
+// metadataLayouts are the ISO 8601 forms used by XMP and DOCX properties
+var metadataLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// metadataList returns the metadata as a one-element list, or nil if no
+// property was set
+func metadataList(meta models.DocumentMetadata) []models.DocumentMetadata {
+	if meta == (models.DocumentMetadata{}) {
+		return nil
+	}
+	return []models.DocumentMetadata{meta}
+}
+
+// xmlFields returns the first non-empty text of each element and attribute
+// in an XML document by local name. Text inside nested elements, such as the
+// rdf:li items of an XMP property, also counts for the elements around it.
+// Fields read before a syntax error are kept.
+func xmlFields(r io.Reader) map[string]string {
+	fields := map[string]string{}
+	set := func(name, value string) {
+		if value = strings.TrimSpace(value); value != "" && fields[name] == "" {
+			fields[name] = value
+		}
+	}
+
+	dec := xml.NewDecoder(r)
+	var open []string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return fields
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			open = append(open, t.Name.Local)
+			for _, attr := range t.Attr {
+				if attr.Name.Space != "xmlns" && attr.Name.Local != "xmlns" {
+					set(attr.Name.Local, attr.Value)
+				}
+			}
+		case xml.EndElement:
+			open = open[:len(open)-1]
+		case xml.CharData:
+			for _, name := range open {
+				set(name, string(t))
+			}
+		}
+	}
+}
+
+// metadataDate parses a PDF date such as D:20240131093000+05'30' or an ISO
+// 8601 date, returning nil if value is neither
+func metadataDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if t, ok := pdfDate(value); ok {
+		return &t
+	}
+	for _, layout := range metadataLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// pdfDate parses a PDF date string. Everything after the year is optional,
+// and a missing time zone is read as UTC.
+func pdfDate(value string) (time.Time, bool) {
+	s := strings.TrimPrefix(value, "D:")
+	digits := len(s) - len(strings.TrimLeft(s, "0123456789"))
+	if digits < 4 || digits > 14 || digits%2 != 0 {
+		return time.Time{}, false
+	}
+
+	// Pad the missing fields with January 1st, midnight
+	stamp := s[:digits] + "0101000000"[digits-4:]
+	zone := strings.ReplaceAll(strings.TrimSuffix(s[digits:], "'"), "'", "")
+	switch {
+	case zone == "" || zone[0] == 'Z':
+		zone = "+0000"
+	case len(zone) == 3:
+		zone += "00"
+	}
+
+	t, err := time.Parse("20060102150405-0700", stamp+zone)
+	return t, err == nil
+}
@@ -0,0 +1,330 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// edgeLines is how many lines at the top and bottom of each page are
+// checked for running headers and footers
+const edgeLines = 2
+
+// Paragraph is a block of normalized text and the page it came from
+type Paragraph struct {
+	Text   string
+	Page   int // 1-based source page, or 0 when the text has no page breaks
+	Offset int // Character offset in the normalized text
+}
+
+// unicodeFixes folds characters that extractors produce for the same text in
+// different ways. Non-breaking and other Unicode spaces are handled by
+// cleanLines.
+var unicodeFixes = strings.NewReplacer(
+	"\r\n", "\n", "\r", "\n",
+	// Ligatures
+	"\ufb00", "ff", "\ufb01", "fi", "\ufb02", "fl", "\ufb03", "ffi", "\ufb04", "ffl", "\ufb05", "st", "\ufb06", "st",
+	// Quotes, primes and guillemets
+	"\u2018", "'", "\u2019", "'", "\u201a", "'", "\u201b", "'", "\u2032", "'",
+	"\u201c", "\"", "\u201d", "\"", "\u201e", "\"", "\u201f", "\"", "\u2033", "\"", "\u00ab", "\"", "\u00bb", "\"",
+	// Hyphens that look like the ASCII one
+	"\u2010", "-", "\u2011", "-",
+	// Line and paragraph separators
+	"\u2028", "\n", "\u2029", "\n",
+	// Soft hyphens and zero-width characters
+	"\u00ad", "", "\u200b", "", "\u200c", "", "\u200d", "", "\u2060", "", "\ufeff", "",
+)
+
+var (
+	// listMarker matches clause numbering and bullets that start a paragraph:
+	// 1.1, 2), (a), iv., • and similar
+	listMarker = regexp.MustCompile(`^(?:\d+(?:\.\d+)+\.?|\d+[.)]|\(\d+\)|\(?[a-zA-Z][.)]|\([a-zA-Z]\)|\(?[ivxlcIVXLC]+[.)]|\([ivxlcIVXLC]+\)|[•·▪‣◦*–-])\s`)
+
+	// pageNumber matches a line holding only a page number
+	pageNumber = regexp.MustCompile(`^(?:-\s*\d{1,4}\s*-|(?i:page|p\.|pg\.?)?\s*\d{1,4}(?:\s*(?i:of|/)\s*\d{1,4})?|[ivx]{1,5})$`)
+
+	// scheduleHeading matches "Schedule 1", "Annexure A - Fees" and similar
+	scheduleHeading = regexp.MustCompile(`^(?i:(schedule|annexure|annex|appendix|exhibit|attachment))\s+(\d{1,3}|[A-Z]|[IVX]{1,5})\b[.:)]?\s*[-–—:]?\s*(.*)$`)
+
+	// sectionHeading matches "Article IV", "Section 3 - Fees" and similar
+	sectionHeading = regexp.MustCompile(`^(?i:(article|section|clause|part))\s+(\d{1,3}|[IVX]{1,5})\b[.:)]?\s*[-–—:]?\s*(.*)$`)
+
+	spaceRun = regexp.MustCompile(` {2,}`)
+	tabRun   = regexp.MustCompile(` *\t[ \t]*`)
+	digitRun = regexp.MustCompile(`\d+`)
+)
+
+// Normalize cleans up the document text and splits it into paragraphs. Page
+// breaks stay as form feeds and paragraphs are separated by blank lines.
+func (d *Document) Normalize() {
+	d.Paragraphs, d.Text = normalize(d.Text)
+}
+
+// NormalizeText cleans up extracted text, keeping paragraph breaks, clause
+// numbering and form feed page breaks
+func NormalizeText(text string) string {
+	_, text = normalize(text)
+	return text
+}
+
+// normalize returns the paragraphs of text and the text rebuilt from them.
+// Unicode variants are folded, running headers, footers and page numbers are
+// removed, and lines wrapped inside a sentence are joined.
+func normalize(text string) ([]Paragraph, string) {
+	pages := strings.Split(unicodeFixes.Replace(text), "\f")
+	lines := make([][]string, len(pages))
+	for i, page := range pages {
+		lines[i] = cleanLines(page)
+	}
+	if len(pages) > 1 {
+		removeRunningLines(lines)
+	}
+
+	var paragraphs []Paragraph
+	texts := make([]string, len(pages))
+	offset := 0
+	for i, pageLines := range lines {
+		page := 0
+		if len(pages) > 1 {
+			page = i + 1
+		}
+		if i > 0 {
+			offset++ // Form feed
+		}
+		paras := joinLines(pageLines)
+		for j, p := range paras {
+			if j > 0 {
+				offset += 2 // Blank line
+			}
+			paragraphs = append(paragraphs, Paragraph{Text: p, Page: page, Offset: offset})
+			offset += utf8.RuneCountInString(p)
+		}
+		texts[i] = strings.Join(paras, "\n\n")
+	}
+
+	// Empty pages keep their form feeds so page numbers still line up
+	return paragraphs, strings.Join(texts, "\f")
+}
+
+// cleanLines splits a page into lines with runs of spaces collapsed. Blank
+// lines are kept as "" to mark paragraph breaks.
+func cleanLines(page string) []string {
+	lines := strings.Split(page, "\n")
+	for i, line := range lines {
+		line = strings.Map(func(r rune) rune {
+			if r != '\t' && unicode.IsSpace(r) {
+				return ' '
+			}
+			if unicode.IsControl(r) && r != '\t' {
+				return -1
+			}
+			return r
+		}, line)
+		line = tabRun.ReplaceAllString(line, "\t")
+		lines[i] = strings.Trim(spaceRun.ReplaceAllString(line, " "), " \t")
+	}
+	return lines
+}
+
+// removeRunningLines blanks page numbers and lines repeated at the top or
+// bottom of most pages, such as letterheads and "Page 2 of 9" footers
+func removeRunningLines(pages [][]string) {
+	// Lines at the same edge of several pages, with digits ignored so that
+	// page counters compare equal
+	counts := map[string]int{}
+	for _, lines := range pages {
+		seen := map[string]bool{}
+		for _, i := range edgeIndexes(lines) {
+			key := runningKey(lines[i])
+			if !seen[key] {
+				seen[key] = true
+				counts[key]++
+			}
+		}
+	}
+
+	threshold := max(2, (len(pages)+1)/2)
+	for _, lines := range pages {
+		for _, i := range edgeIndexes(lines) {
+			if counts[runningKey(lines[i])] >= threshold || pageNumber.MatchString(lines[i]) {
+				lines[i] = ""
+			}
+		}
+		// A page number may sit inside the running lines just removed
+		for _, i := range edgeIndexes(lines) {
+			if pageNumber.MatchString(lines[i]) {
+				lines[i] = ""
+			}
+		}
+	}
+}
+
+// edgeIndexes returns the indexes of the first and last non-blank lines of a
+// page
+func edgeIndexes(lines []string) []int {
+	var top, bottom []int
+	for i := 0; i < len(lines) && len(top) < edgeLines; i++ {
+		if lines[i] != "" {
+			top = append(top, i)
+		}
+	}
+	for i := len(lines) - 1; i >= 0 && len(bottom) < edgeLines; i-- {
+		if lines[i] != "" && (len(top) == 0 || i > top[len(top)-1]) {
+			bottom = append(bottom, i)
+		}
+	}
+	return append(top, bottom...)
+}
+
+func runningKey(line string) string {
+	return strings.ToLower(digitRun.ReplaceAllString(line, "#"))
+}
+
+// joinLines groups a page's lines into paragraphs. A blank line or a clause
+// number starts a new paragraph; a line that continues a sentence is joined
+// to the one before it, removing the hyphen of a word broken across lines.
+func joinLines(lines []string) []string {
+	var paras []string
+	var cur strings.Builder
+	joined := 0 // Lines in cur
+	end := func() {
+		if cur.Len() > 0 {
+			paras = append(paras, cur.String())
+			cur.Reset()
+		}
+		joined = 0
+	}
+
+	for _, line := range lines {
+		switch {
+		case line == "":
+			end()
+			continue
+		case cur.Len() == 0:
+			cur.WriteString(line)
+		case listMarker.MatchString(line) || !continuesLine(cur.String(), line, joined):
+			end()
+			cur.WriteString(line)
+		case brokenWord(cur.String(), line):
+			prev := strings.TrimSuffix(cur.String(), "-")
+			cur.Reset()
+			cur.WriteString(prev)
+			cur.WriteString(line)
+		default:
+			cur.WriteString(" ")
+			cur.WriteString(line)
+		}
+		joined++
+	}
+	end()
+	return paras
+}
+
+// continuesLine reports whether next reads as the rest of a sentence that
+// prev wrapped. A line starting in lower case always continues; otherwise
+// next continues unless prev ends a sentence, next is a heading, or prev is a
+// single line that reads as a heading or salutation. Contracts often wrap
+// before a capitalized defined term, as in "the\nEmployee named below".
+func continuesLine(prev, next string, prevLines int) bool {
+	if strings.Contains(prev, "\t") || strings.Contains(next, "\t") {
+		// Table rows stay on their own lines
+		return false
+	}
+	first, _ := utf8.DecodeRuneInString(next)
+	if unicode.IsLower(first) {
+		return true
+	}
+	if endsSentence(prev) || isHeadingLine(next) {
+		return false
+	}
+	return prevLines > 1 || !(isHeadingLine(prev) || isTitleLine(prev) || isSalutation(prev))
+}
+
+// endsSentence reports whether a line ends in terminal punctuation, ignoring
+// closing quotes and brackets
+func endsSentence(line string) bool {
+	line = strings.TrimRight(line, "\"')]")
+	last, _ := utf8.DecodeLastRuneInString(line)
+	return strings.ContainsRune(".;:!?", last)
+}
+
+// isHeadingLine reports whether a line is a section or schedule heading or
+// an upper case heading such as "TERMINATION"
+func isHeadingLine(line string) bool {
+	if m := sectionHeading.FindStringSubmatch(line); m != nil && isTitle(m[3], true) {
+		return true
+	}
+	if m := scheduleHeading.FindStringSubmatch(line); m != nil && isTitle(m[3], true) {
+		return true
+	}
+	return isCapsHeading(line)
+}
+
+// isTitleLine reports whether a line, after any clause number, reads as a
+// title such as "5. Termination of Employment" or a letterhead line: short,
+// without closing punctuation and with every longer word capitalized
+func isTitleLine(line string) bool {
+	words := strings.Fields(listMarker.ReplaceAllString(line, ""))
+	if len(words) > 10 {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(line)
+	if strings.ContainsRune(".;:,!?", last) {
+		return false
+	}
+	for _, word := range words {
+		letters := strings.IndexFunc(word, unicode.IsLetter)
+		if letters < 0 || utf8.RuneCountInString(word[letters:]) <= 3 {
+			continue // Numbers and short words such as "of" or "and"
+		}
+		if r, _ := utf8.DecodeRuneInString(word[letters:]); unicode.IsLower(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSalutation reports whether a line is a short greeting or closing such as
+// "Dear Ms. Rao," or "Yours sincerely,"
+func isSalutation(line string) bool {
+	return strings.HasSuffix(line, ",") && len(strings.Fields(line)) <= 4
+}
+
+// brokenWord reports whether prev ends with a word hyphenated at the line
+// break and next continues it
+func brokenWord(prev, next string) bool {
+	if !strings.HasSuffix(prev, "-") || len(prev) < 2 {
+		return false
+	}
+	before, _ := utf8.DecodeLastRuneInString(prev[:len(prev)-1])
+	first, _ := utf8.DecodeRuneInString(next)
+	return unicode.IsLetter(before) && unicode.IsLower(first)
+}
+
+// isTitle reports whether the text after a number reads as a heading rather
+// than a sentence: short, capitalized and without closing punctuation
+func isTitle(text string, allowEmpty bool) bool {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return allowEmpty
+	}
+	first, _ := utf8.DecodeRuneInString(text)
+	last, _ := utf8.DecodeLastRuneInString(text)
+	return !unicode.IsLower(first) && !strings.ContainsRune(".;:,", last) && len(strings.Fields(text)) <= 10
+}
+
+// isCapsHeading reports whether a paragraph is an unnumbered upper case
+// heading such as "TERMINATION"
+func isCapsHeading(text string) bool {
+	letters := 0
+	for _, r := range text {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters >= 3 && len(strings.Fields(text)) <= 8
+}
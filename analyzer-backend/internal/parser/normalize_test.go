@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestJoinLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "lower case continuation",
+			lines: []string{"The Employee shall be paid", "monthly in arrears."},
+			want:  []string{"The Employee shall be paid monthly in arrears."},
+		},
+		{
+			name:  "capitalized defined term",
+			lines: []string{"This agreement is made between the Company and the", "Employee named below."},
+			want:  []string{"This agreement is made between the Company and the Employee named below."},
+		},
+		{
+			name:  "capitalized word after a preposition",
+			lines: []string{"1.2 The Employee shall perform the duties assigned by", "Management from time to time."},
+			want:  []string{"1.2 The Employee shall perform the duties assigned by Management from time to time."},
+		},
+		{
+			name:  "wrapped over several lines",
+			lines: []string{"The Employee agrees that the", "Employer may assign the", "Employee to any Affiliate."},
+			want:  []string{"The Employee agrees that the Employer may assign the Employee to any Affiliate."},
+		},
+		{
+			name:  "sentence end",
+			lines: []string{"The Employee shall be paid monthly.", "Bonuses are discretionary."},
+			want:  []string{"The Employee shall be paid monthly.", "Bonuses are discretionary."},
+		},
+		{
+			name:  "sentence end inside quotes",
+			lines: []string{`This is the "Agreement."`, "It replaces all prior offers."},
+			want:  []string{`This is the "Agreement."`, "It replaces all prior offers."},
+		},
+		{
+			name:  "colon and semicolon",
+			lines: []string{"The Employee shall:", "Keep records;", "Report weekly"},
+			want:  []string{"The Employee shall:", "Keep records;", "Report weekly"},
+		},
+		{
+			name:  "list marker",
+			lines: []string{"The Employee shall", "(a) keep records", "2. Termination"},
+			want:  []string{"The Employee shall", "(a) keep records", "2. Termination"},
+		},
+		{
+			name:  "numbered title",
+			lines: []string{"5. Termination of Employment", "The Employer may end this agreement on notice."},
+			want:  []string{"5. Termination of Employment", "The Employer may end this agreement on notice."},
+		},
+		{
+			name:  "upper case heading",
+			lines: []string{"The terms are set out below", "CONFIDENTIALITY", "The Employee shall keep secrets."},
+			want:  []string{"The terms are set out below", "CONFIDENTIALITY", "The Employee shall keep secrets."},
+		},
+		{
+			name:  "section heading",
+			lines: []string{"The fees are described in", "Section 4 - Fees", "Fees are due monthly."},
+			want:  []string{"The fees are described in", "Section 4 - Fees", "Fees are due monthly."},
+		},
+		{
+			name:  "section reference",
+			lines: []string{"Notice is given as described in", "Section 4 of this Agreement."},
+			want:  []string{"Notice is given as described in Section 4 of this Agreement."},
+		},
+		{
+			name:  "letterhead and salutation",
+			lines: []string{"Acme Technologies Pvt Ltd", "12 Park Street", "Dear Ms. Rao,", "We are pleased to offer you the role."},
+			want:  []string{"Acme Technologies Pvt Ltd", "12 Park Street", "Dear Ms. Rao,", "We are pleased to offer you the role."},
+		},
+		{
+			name:  "hyphenated word",
+			lines: []string{"The Employee shall be reim-", "bursed for travel."},
+			want:  []string{"The Employee shall be reimbursed for travel."},
+		},
+		{
+			name:  "table rows",
+			lines: []string{"Basic\t50,000", "Allowance\t10,000"},
+			want:  []string{"Basic\t50,000", "Allowance\t10,000"},
+		},
+		{
+			name:  "blank line",
+			lines: []string{"The Employee shall be paid", "", "Monthly payments are made by transfer."},
+			want:  []string{"The Employee shall be paid", "Monthly payments are made by transfer."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinLines(tt.lines); !slices.Equal(got, tt.want) {
+				t.Errorf("joinLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeParagraphPages(t *testing.T) {
+	text := "Offer Letter\nPage 1 of 2\fThe Employee shall report to the\nChief Executive Officer of the Company.\nPage 2 of 2"
+
+	paragraphs, normalized := normalize(text)
+
+	want := []Paragraph{
+		{Text: "Offer Letter", Page: 1, Offset: 0},
+		{Text: "The Employee shall report to the Chief Executive Officer of the Company.", Page: 2, Offset: 13},
+	}
+	if !slices.Equal(paragraphs, want) {
+		t.Errorf("normalize() paragraphs = %+v, want %+v", paragraphs, want)
+	}
+	if wantText := "Offer Letter\fThe Employee shall report to the Chief Executive Officer of the Company."; normalized != wantText {
+		t.Errorf("normalize() text = %q, want %q", normalized, wantText)
+	}
+}
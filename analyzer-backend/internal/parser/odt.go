@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	odfTextNS   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	odfOfficeNS = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odfTableNS  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+)
+
+// parseODT extracts text from an OpenDocument text file's content.xml.
+// Paragraphs, headings and table rows end with a newline and table cells are
+// separated by tabs. Comments and deleted tracked changes are skipped.
+func parseODT(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to open ODT: %w", err)
+	}
+
+	var content *zip.File
+	for _, f := range archive.File {
+		if f.Name == "content.xml" {
+			content = f
+			break
+		}
+	}
+	if content == nil {
+		return "", fmt.Errorf("failed to parse ODT: content.xml not found")
+	}
+
+	rc, err := openZipFile(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse ODT: %w", err)
+	}
+	defer rc.Close()
+
+	var buf strings.Builder
+	dec := xml.NewDecoder(rc)
+	skip := 0  // Depth inside elements whose text is not part of the document
+	paras := 0 // Depth inside paragraphs and headings; text elsewhere is layout whitespace
+	cells := 0 // Depth inside table cells, where paragraphs are joined on one line
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse ODT: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if skip > 0 || odtSkipped(t.Name) {
+				skip++
+				continue
+			}
+			switch {
+			case t.Name.Space == odfTableNS && t.Name.Local == "table-cell":
+				cells++
+				continue
+			case t.Name.Space != odfTextNS:
+				continue
+			}
+			switch t.Name.Local {
+			case "p", "h":
+				paras++
+			case "s":
+				// Runs of spaces are stored as a count
+				n := 1
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "c" {
+						if c, err := strconv.Atoi(attr.Value); err == nil && c > 0 {
+							n = c
+						}
+					}
+				}
+				buf.WriteString(strings.Repeat(" ", n))
+			case "tab":
+				buf.WriteString("\t")
+			case "line-break":
+				buf.WriteString("\n")
+			}
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			switch {
+			case t.Name.Space == odfTextNS && (t.Name.Local == "p" || t.Name.Local == "h"):
+				paras--
+				if cells > 0 {
+					buf.WriteString(" ")
+				} else {
+					buf.WriteString("\n")
+				}
+			case t.Name.Space == odfTableNS && t.Name.Local == "table-cell":
+				cells--
+				buf.WriteString("\t")
+			case t.Name.Space == odfTableNS && t.Name.Local == "table-row":
+				buf.WriteString("\n")
+			}
+		case xml.CharData:
+			if skip == 0 && paras > 0 {
+				buf.Write(t)
+			}
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// odtSkipped reports whether an element's text is left out of the extraction
+func odtSkipped(name xml.Name) bool {
+	switch {
+	case name.Space == odfOfficeNS && name.Local == "annotation":
+		return true
+	case name.Space == odfTextNS && name.Local == "tracked-changes":
+		return true
+	}
+	return false
+}
@@ -1,125 +1,56 @@
 package parser
 
 import (
-	"bytes"
 	"encoding/base64"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/ledongthuc/pdf"
-	"github.com/nguyenthenguyen/docx"
+	"ea-scanner/internal/models"
 )
 
-// ParseDocument extracts text content from base64 encoded document
-func ParseDocument(base64Content, filename string) (string, error) {
+// Document is the text extracted from an uploaded document
+type Document struct {
+	Text           string
+	Paragraphs     []Paragraph               // Set by Normalize
+	Clauses        []*models.Clause          // Clause tree built from the paragraphs by Segment
+	Format         Format                    // Detected from the content, not the filename
+	Email          *models.EmailHeaders      // Headers of an email, for sender checks
+	Attachments    []string                  // Names of attachments whose text was included
+	TrackedChanges []models.TrackedChange    // Unresolved tracked changes, for formats that record them
+	Pages          int                       // Page count, for paged formats such as PDF
+	EmptyPages     []int                     // 1-based pages without extractable text, such as scanned images
+	Metadata       []models.DocumentMetadata // Document properties of the file and of email attachments
+	Links          []string                  // Hyperlink targets, which need not appear in the text
+	Contacts       []models.Contact          // URLs, email addresses, phone numbers and domains, set by ExtractContacts
+}
+
+// ParseDocument extracts text content from base64 encoded document. The
+// format is detected from the content and parsed with its registered
+// extractor; a known extension that disagrees with it is rejected with a
+// *MismatchError.
+func ParseDocument(base64Content, filename string) (*Document, error) {
 	// Decode base64
 	data, err := base64.StdEncoding.DecodeString(base64Content)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode base64: %w", err)
+		return nil, fmt.Errorf("failed to decode base64: %w", err)
 	}
 
-	ext := strings.ToLower(filepath.Ext(filename))
-
-	switch ext {
-	case ".txt":
-		return string(data), nil
-	case ".docx":
-		return parseDocx(data)
-	case ".pdf":
-		return parsePDF(data)
-	default:
-		// Try as plain text
-		return string(data), nil
-	}
+	return parse(data, filename)
 }
 
-// parseDocx extracts text from DOCX bytes
-func parseDocx(data []byte) (string, error) {
-	// Write to temp file for docx library
-	tmpFile, err := os.CreateTemp("", "ea-doc-*.docx")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
-	}
-	defer os.Remove(tmpFile.Name())
-	defer tmpFile.Close()
-
-	if _, err := tmpFile.Write(data); err != nil {
-		return "", fmt.Errorf("failed to write temp file: %w", err)
-	}
-	tmpFile.Close()
-
-	// Read docx from file
-	doc, err := docx.ReadDocxFile(tmpFile.Name())
+// parse extracts text from raw document bytes
+func parse(data []byte, filename string) (*Document, error) {
+	spec, err := resolve(Sniff(data), strings.ToLower(filepath.Ext(filename)))
 	if err != nil {
-		return "", fmt.Errorf("failed to parse DOCX: %w", err)
+		return nil, err
 	}
-	defer doc.Close()
 
-	// Get editable content
-	content := doc.Editable().GetContent()
-
-	return content, nil
-}
-
-// parsePDF extracts text from PDF bytes using ledongthuc/pdf library
-func parsePDF(data []byte) (string, error) {
-	// Write to temp file for pdf library
-	tmpFile, err := os.CreateTemp("", "ea-pdf-*.pdf")
+	doc, err := spec.Extract(data)
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
-	}
-	defer os.Remove(tmpFile.Name())
-
-	if _, err := tmpFile.Write(data); err != nil {
-		tmpFile.Close()
-		return "", fmt.Errorf("failed to write temp file: %w", err)
+		return nil, err
 	}
-	tmpFile.Close()
-
-	// Open PDF file
-	f, r, err := pdf.Open(tmpFile.Name())
-	if err != nil {
-		return "", fmt.Errorf("failed to open PDF: %w", err)
-	}
-	defer f.Close()
-
-	// Extract text from all pages
-	var buf bytes.Buffer
-	totalPages := r.NumPage()
-
-	for pageNum := 1; pageNum <= totalPages; pageNum++ {
-		page := r.Page(pageNum)
-		if page.V.IsNull() {
-			continue
-		}
-
-		text, err := page.GetPlainText(nil)
-		if err != nil {
-			// Try to continue with other pages
-			continue
-		}
-		buf.WriteString(text)
-		buf.WriteString("\n")
-	}
-
-	content := buf.String()
-	if strings.TrimSpace(content) == "" {
-		return "", fmt.Errorf("could not extract text from PDF - the PDF may be image-based or encrypted")
-	}
-
-	return content, nil
-}
-
-// NormalizeText cleans up extracted text
-func NormalizeText(text string) string {
-	// Remove excessive whitespace
-	text = strings.Join(strings.Fields(text), " ")
-
-	// Basic cleanup
-	text = strings.ReplaceAll(text, "\r\n", "\n")
-	text = strings.ReplaceAll(text, "\r", "\n")
+	doc.Format = spec.Format
 
-	return strings.TrimSpace(text)
+	return doc, nil
 }
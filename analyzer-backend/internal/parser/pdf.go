@@ -0,0 +1,424 @@
+package parser
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+
+	"ea-scanner/internal/models"
+)
+
+// Layout thresholds, as multiples of the font size
+const (
+	pdfLineTolerance = 0.3  // Vertical distance within which glyphs share a line
+	pdfSpaceGap      = 0.15 // Horizontal gap that separates words
+	pdfCellGap       = 2.0  // Horizontal gap that separates table cells or columns
+	pdfParagraphGap  = 1.8  // Line spacing that starts a new paragraph
+)
+
+// Column detection
+const (
+	pdfMinGutter    = 12   // Narrowest gap between columns, in points
+	pdfMaxCrossing  = 0.1  // Share of lines that may span a gutter, such as a heading
+	pdfMinOneSided  = 0.3  // Share of lines on only one side, which tells columns from tables
+	pdfMaxColumnize = 3    // Column splits followed on one page
+	pdfGlyphWidth   = 0.5  // Width assumed for glyphs whose font has no widths
+	pdfMaxBands     = 2000 // Bands the page width is divided into when searching for a gutter
+)
+
+// pdfSpan is a run of text on one line with no large gap inside
+type pdfSpan struct {
+	x0, x1 float64
+	text   string
+}
+
+// pdfLine is a row of spans sharing a baseline
+type pdfLine struct {
+	y     float64
+	size  float64 // Largest font size on the line
+	spans []pdfSpan
+}
+
+func (l pdfLine) x0() float64 { return l.spans[0].x0 }
+func (l pdfLine) x1() float64 { return l.spans[len(l.spans)-1].x1 }
+
+// parsePDF extracts text from PDF bytes in reading order. Glyphs are grouped
+// into lines by position, side-by-side columns are read one after the other,
+// and wide gaps within a line become tabs. Pages are separated by form feeds;
+// pages without text, such as scanned images, are listed in EmptyPages, and
+// link annotation targets in Links. The document is read in memory so
+// uploads never touch the filesystem.
+func parsePDF(data []byte) (*Document, error) {
+	r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+
+	doc := &Document{Pages: r.NumPage(), Metadata: metadataList(pdfMetadata(r))}
+	pages := make([]string, doc.Pages)
+	for i := range pages {
+		page := r.Page(i + 1)
+		pages[i] = pdfPageText(page)
+		doc.Links = append(doc.Links, pdfPageLinks(page)...)
+		if strings.TrimSpace(pages[i]) == "" {
+			doc.EmptyPages = append(doc.EmptyPages, i+1)
+		}
+	}
+
+	if len(doc.EmptyPages) == doc.Pages {
+		return nil, fmt.Errorf("could not extract text from PDF - the PDF may be image-based or encrypted")
+	}
+	// Form feeds mark the page breaks
+	doc.Text = strings.Join(pages, "\f")
+
+	return doc, nil
+}
+
+// pdfMetadata reads the document information dictionary, filling properties
+// it lacks from the XMP metadata stream
+func pdfMetadata(r *pdf.Reader) (meta models.DocumentMetadata) {
+	defer func() {
+		// Keep what was read before a malformed object
+		recover()
+	}()
+
+	info := r.Trailer().Key("Info")
+	text := func(key string) string {
+		return strings.TrimSpace(info.Key(key).Text())
+	}
+	meta = models.DocumentMetadata{
+		Title:    text("Title"),
+		Author:   text("Author"),
+		Creator:  text("Creator"),
+		Producer: text("Producer"),
+		Created:  metadataDate(text("CreationDate")),
+		Modified: metadataDate(text("ModDate")),
+	}
+
+	stream := r.Trailer().Key("Root").Key("Metadata")
+	if stream.Kind() != pdf.Stream {
+		return meta
+	}
+	rc := stream.Reader()
+	defer rc.Close()
+	xmp := xmlFields(rc)
+
+	meta.Title = cmp.Or(meta.Title, xmp["title"])
+	meta.Author = cmp.Or(meta.Author, xmp["creator"])
+	meta.Creator = cmp.Or(meta.Creator, xmp["CreatorTool"])
+	meta.Producer = cmp.Or(meta.Producer, xmp["Producer"])
+	if meta.Created == nil {
+		meta.Created = metadataDate(xmp["CreateDate"])
+	}
+	if meta.Modified == nil {
+		meta.Modified = metadataDate(xmp["ModifyDate"])
+	}
+	return meta
+}
+
+// pdfPageLinks returns the targets of a page's link annotations
+func pdfPageLinks(page pdf.Page) (links []string) {
+	defer func() {
+		// Keep the links read before a malformed annotation
+		recover()
+	}()
+
+	annots := page.V.Key("Annots")
+	for i := 0; i < annots.Len(); i++ {
+		annot := annots.Index(i)
+		if annot.Key("Subtype").Name() != "Link" {
+			continue
+		}
+		if target, ok := linkTarget(annot.Key("A").Key("URI").RawString()); ok {
+			links = append(links, target)
+		}
+	}
+	return links
+}
+
+// pdfPageText returns the text of one page in reading order, or "" if the
+// page has no text or cannot be read
+func pdfPageText(page pdf.Page) (text string) {
+	if page.V.IsNull() {
+		return ""
+	}
+	defer func() {
+		// The content interpreter panics on malformed streams
+		if recover() != nil {
+			text = ""
+		}
+	}()
+
+	lines := pdfLines(page.Content().Text)
+	if len(lines) == 0 {
+		return ""
+	}
+
+	var buf strings.Builder
+	pdfWriteLines(&buf, lines, 0)
+	return buf.String()
+}
+
+// pdfLines groups glyphs into lines from top to bottom, each with its
+// glyphs joined into spans from left to right
+func pdfLines(glyphs []pdf.Text) []pdfLine {
+	glyphs = slices.DeleteFunc(slices.Clone(glyphs), func(g pdf.Text) bool {
+		return strings.TrimSpace(g.S) == "" && g.S != " "
+	})
+	slices.SortStableFunc(glyphs, func(a, b pdf.Text) int {
+		return cmp.Compare(b.Y, a.Y)
+	})
+
+	var lines []pdfLine
+	var row []pdf.Text
+	flush := func() {
+		if line, ok := pdfLineOf(row); ok {
+			lines = append(lines, line)
+		}
+		row = row[:0]
+	}
+	for _, g := range glyphs {
+		if len(row) > 0 {
+			tolerance := pdfLineTolerance * max(g.FontSize, row[0].FontSize, 1)
+			if math.Abs(row[0].Y-g.Y) > tolerance {
+				flush()
+			}
+		}
+		row = append(row, g)
+	}
+	flush()
+
+	return lines
+}
+
+// pdfLineOf joins the glyphs of one line into spans
+func pdfLineOf(glyphs []pdf.Text) (pdfLine, bool) {
+	slices.SortStableFunc(glyphs, func(a, b pdf.Text) int {
+		return cmp.Compare(a.X, b.X)
+	})
+
+	line := pdfLine{y: glyphs[0].Y}
+	var cur *pdfSpan
+	var word strings.Builder
+	end := func() {
+		if cur != nil {
+			if cur.text = strings.TrimSpace(word.String()); cur.text != "" {
+				line.spans = append(line.spans, *cur)
+			}
+			cur = nil
+			word.Reset()
+		}
+	}
+
+	for _, g := range glyphs {
+		size := max(g.FontSize, 1)
+		line.size = max(line.size, size)
+		width := g.W
+		if width <= 0 {
+			width = pdfGlyphWidth * size * float64(len([]rune(g.S)))
+		}
+
+		if cur != nil {
+			gap := g.X - cur.x1
+			switch {
+			case gap > pdfCellGap*size:
+				end()
+			case gap > pdfSpaceGap*size && !strings.HasSuffix(word.String(), " ") && g.S != " ":
+				word.WriteString(" ")
+			}
+		}
+		if cur == nil {
+			if g.S == " " {
+				continue
+			}
+			cur = &pdfSpan{x0: g.X}
+		}
+		word.WriteString(g.S)
+		cur.x1 = max(cur.x1, g.X+width)
+	}
+	end()
+
+	return line, len(line.spans) > 0
+}
+
+// pdfWriteLines writes lines in reading order. Blocks separated by blank
+// space across the page are laid out on their own; if a block forms columns,
+// each column is written in turn, with lines spanning the gutter, such as
+// headings, keeping their place between them.
+func pdfWriteLines(buf *strings.Builder, lines []pdfLine, depth int) {
+	if depth >= pdfMaxColumnize {
+		pdfWriteRows(buf, lines)
+		return
+	}
+	for _, block := range pdfBlocks(lines) {
+		gutter, ok := pdfGutter(block)
+		if !ok {
+			pdfWriteRows(buf, block)
+			continue
+		}
+		var left, right []pdfLine
+		flushColumns := func() {
+			pdfWriteLines(buf, left, depth+1)
+			pdfWriteLines(buf, right, depth+1)
+			left, right = nil, nil
+		}
+		for _, line := range block {
+			l, r, crossing := pdfSplitLine(line, gutter)
+			if crossing {
+				flushColumns()
+				pdfWriteRows(buf, []pdfLine{line})
+				continue
+			}
+			if l != nil {
+				left = append(left, *l)
+			}
+			if r != nil {
+				right = append(right, *r)
+			}
+		}
+		flushColumns()
+	}
+}
+
+// pdfBlocks splits lines, sorted from top to bottom, where the space between
+// two lines would start a new paragraph. Columns rarely break at the same
+// height, so this separates a header or a table from the columns around it.
+func pdfBlocks(lines []pdfLine) [][]pdfLine {
+	var blocks [][]pdfLine
+	start := 0
+	for i := 1; i < len(lines); i++ {
+		if pdfParagraphBreak(lines[i-1], lines[i]) {
+			blocks = append(blocks, lines[start:i])
+			start = i
+		}
+	}
+	if start < len(lines) {
+		blocks = append(blocks, lines[start:])
+	}
+	return blocks
+}
+
+// pdfWriteRows writes lines as a new paragraph, one row per line with tabs
+// between spans and a blank line where the spacing starts another paragraph
+func pdfWriteRows(buf *strings.Builder, lines []pdfLine) {
+	for i, line := range lines {
+		switch {
+		case i == 0 || pdfParagraphBreak(lines[i-1], line):
+			if text := buf.String(); text != "" && !strings.HasSuffix(text, "\n\n") {
+				if !strings.HasSuffix(text, "\n") {
+					buf.WriteString("\n")
+				}
+				buf.WriteString("\n")
+			}
+		default:
+			buf.WriteString("\n")
+		}
+		for j, span := range line.spans {
+			if j > 0 {
+				buf.WriteString("\t")
+			}
+			buf.WriteString(span.text)
+		}
+	}
+}
+
+// pdfParagraphBreak reports whether the space between two consecutive lines
+// starts a new paragraph
+func pdfParagraphBreak(prev, next pdfLine) bool {
+	return prev.y-next.y > pdfParagraphGap*max(prev.size, next.size)
+}
+
+// pdfGutter finds a vertical gap between two columns of text: a band that
+// few lines cross, with text on both sides and many lines that have text on
+// only one side, which tells columns from table rows. Columns whose lines
+// all share baselines cannot be told from a table and are kept as rows.
+func pdfGutter(lines []pdfLine) (float64, bool) {
+	if len(lines) < 4 {
+		return 0, false
+	}
+	left, right := lines[0].x0(), lines[0].x1()
+	for _, line := range lines {
+		left, right = min(left, line.x0()), max(right, line.x1())
+	}
+	width := right - left
+	if math.IsNaN(width) || math.IsInf(width, 0) || width < 4*pdfMinGutter {
+		return 0, false
+	}
+
+	// Lines covering each band of the page. Bands are one point wide unless
+	// glyph positions, which come from the file, span more than pdfMaxBands
+	// points.
+	scale := max(1, width/pdfMaxBands)
+	cover := make([]int, int(width/scale)+1)
+	for _, line := range lines {
+		for _, span := range line.spans {
+			for x := int((span.x0 - left) / scale); x < int((span.x1-left)/scale) && x < len(cover); x++ {
+				cover[x]++
+			}
+		}
+	}
+
+	maxCrossing := int(pdfMaxCrossing * float64(len(lines)))
+	best, bestWidth := 0.0, 0
+	for start := int(float64(len(cover)) * 0.15); start < int(float64(len(cover))*0.85); {
+		if cover[start] > maxCrossing {
+			start++
+			continue
+		}
+		end := start
+		for end < len(cover) && cover[end] <= maxCrossing {
+			end++
+		}
+		if float64(end-start)*scale >= pdfMinGutter && end-start > bestWidth {
+			best, bestWidth = left+float64(start+end)/2*scale, end-start
+		}
+		start = end
+	}
+	if bestWidth == 0 {
+		return 0, false
+	}
+
+	var leftOnly, rightOnly, both int
+	for _, line := range lines {
+		l, r, crossing := pdfSplitLine(line, best)
+		switch {
+		case crossing:
+		case l != nil && r != nil:
+			both++
+		case l != nil:
+			leftOnly++
+		default:
+			rightOnly++
+		}
+	}
+	sided := float64(leftOnly+rightOnly) / float64(len(lines))
+	return best, leftOnly+both > 0 && rightOnly+both > 0 && sided >= pdfMinOneSided
+}
+
+// pdfSplitLine divides a line at the gutter. It reports crossing if a span
+// runs across it.
+func pdfSplitLine(line pdfLine, gutter float64) (left, right *pdfLine, crossing bool) {
+	var l, r []pdfSpan
+	for _, span := range line.spans {
+		switch {
+		case span.x1 <= gutter:
+			l = append(l, span)
+		case span.x0 >= gutter:
+			r = append(r, span)
+		default:
+			return nil, nil, true
+		}
+	}
+	if len(l) > 0 {
+		left = &pdfLine{y: line.y, size: line.size, spans: l}
+	}
+	if len(r) > 0 {
+		right = &pdfLine{y: line.y, size: line.size, spans: r}
+	}
+	return left, right, false
+}
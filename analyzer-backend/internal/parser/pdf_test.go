@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// pdfText lays out s as 10-point glyphs, each 5 points wide, starting at x
+// on baseline y
+func pdfText(x, y float64, s string) []pdf.Text {
+	var glyphs []pdf.Text
+	for i, r := range []rune(s) {
+		glyphs = append(glyphs, pdf.Text{FontSize: 10, X: x + 5*float64(i), Y: y, W: 5, S: string(r)})
+	}
+	return glyphs
+}
+
+func TestPDFLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		glyphs [][]pdf.Text
+		want   string
+	}{
+		{
+			name:   "lines of one paragraph",
+			glyphs: [][]pdf.Text{pdfText(72, 700, "Offer of employment"), pdfText(72, 688, "for the role")},
+			want:   "Offer of employment\nfor the role",
+		},
+		{
+			name:   "paragraph spacing",
+			glyphs: [][]pdf.Text{pdfText(72, 700, "Dear Ms. Rao,"), pdfText(72, 660, "Welcome.")},
+			want:   "Dear Ms. Rao,\n\nWelcome.",
+		},
+		{
+			name:   "glyphs out of order",
+			glyphs: [][]pdf.Text{pdfText(102, 700, "world"), pdfText(72, 700, "Hello")},
+			want:   "Hello world",
+		},
+		{
+			name: "table cells",
+			glyphs: [][]pdf.Text{
+				pdfText(72, 700, "Basic"), pdfText(300, 700, "50,000"),
+				pdfText(72, 688, "Allowance"), pdfText(300, 688, "10,000"),
+			},
+			want: "Basic\t50,000\nAllowance\t10,000",
+		},
+		{
+			name: "two columns",
+			glyphs: [][]pdf.Text{
+				pdfText(72, 700, "Left one"), pdfText(320, 694, "Right one"),
+				pdfText(72, 688, "Left two"), pdfText(320, 682, "Right two"),
+				pdfText(72, 676, "Left three"), pdfText(320, 670, "Right three"),
+				pdfText(72, 664, "Left four"), pdfText(320, 658, "Right four"),
+			},
+			want: "Left one\nLeft two\nLeft three\nLeft four\n\nRight one\nRight two\nRight three\nRight four",
+		},
+		{
+			name: "heading across two columns",
+			glyphs: [][]pdf.Text{
+				pdfText(72, 730, "Terms and conditions of the employment agreement"),
+				pdfText(72, 712, "Left one"), pdfText(320, 706, "Right one"),
+				pdfText(72, 700, "Left two"), pdfText(320, 694, "Right two"),
+				pdfText(72, 688, "Left three"), pdfText(320, 682, "Right three"),
+				pdfText(72, 676, "Left four"), pdfText(320, 670, "Right four"),
+				pdfText(72, 664, "Left five"), pdfText(320, 658, "Right five"),
+			},
+			want: "Terms and conditions of the employment agreement\n\nLeft one\nLeft two\nLeft three\nLeft four\nLeft five\n\nRight one\nRight two\nRight three\nRight four\nRight five",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := pdfLines(slices.Concat(tt.glyphs...))
+			var buf strings.Builder
+			pdfWriteLines(&buf, lines, 0)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("layout = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPDFGutterFarGlyph(t *testing.T) {
+	glyphs := slices.Concat(
+		pdfText(72, 700, "Left one"), pdfText(320, 694, "Right one"),
+		pdfText(72, 688, "Left two"), pdfText(320, 682, "Right two"),
+		pdfText(1e9, 676, "far"), pdfText(1e18, 670, "farther"),
+	)
+	// Must not allocate a band per point up to the far glyphs
+	pdfGutter(pdfLines(glyphs))
+}
+
+// minimalPDF builds a PDF with one page per content stream, using a standard
+// font, so that empty pages can be tested
+func minimalPDF(contents ...string) []byte {
+	var objects []string
+	kids := make([]string, len(contents))
+	for i, content := range contents {
+		page, stream := 4+2*i, 5+2*i
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", stream),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+	objects = slices.Insert(objects, 0,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(contents)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	)
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func TestParsePDFEmptyPages(t *testing.T) {
+	text := "BT /F1 12 Tf 72 700 Td (Offer of employment) Tj ET"
+
+	doc, err := parsePDF(minimalPDF(text, "", text))
+	if err != nil {
+		t.Fatalf("parsePDF() error = %v", err)
+	}
+	if doc.Pages != 3 || !slices.Equal(doc.EmptyPages, []int{2}) {
+		t.Errorf("pages = %d, empty pages = %v; want 3 and [2]", doc.Pages, doc.EmptyPages)
+	}
+	if pages := strings.Split(doc.Text, "\f"); len(pages) != 3 || !strings.Contains(pages[0], "Offer of employment") || pages[1] != "" {
+		t.Errorf("text = %q, want the text on pages 1 and 3", doc.Text)
+	}
+
+	if _, err := parsePDF(minimalPDF("")); err == nil {
+		t.Error("parsePDF() of a page without text succeeded, want error")
+	}
+}
@@ -0,0 +1,78 @@
+package parser
+
+import "strings"
+
+// Extractor parses a document's raw bytes. The parser sets the returned
+// document's Format.
+type Extractor func(data []byte) (*Document, error)
+
+// TextExtractor adapts a function that only returns text to an Extractor
+func TextExtractor(extract func(data []byte) (string, error)) Extractor {
+	return func(data []byte) (*Document, error) {
+		text, err := extract(data)
+		if err != nil {
+			return nil, err
+		}
+		return &Document{Text: text}, nil
+	}
+}
+
+// Spec registers a document format with the parser
+type Spec struct {
+	Format     Format
+	Extensions []string  // Extensions that imply the format, e.g. ".odt"
+	PlainText  bool      // Content sniffs as plain text, so the format is chosen by extension
+	Extract    Extractor // Parses the document
+}
+
+var (
+	formats    = map[Format]Spec{}
+	extensions = map[string]Format{}
+)
+
+func init() {
+	for _, spec := range []Spec{
+		{Format: FormatText, Extensions: []string{".txt", ".text"}, Extract: TextExtractor(extractText)},
+		{Format: FormatPDF, Extensions: []string{".pdf"}, Extract: parsePDF},
+		{Format: FormatDOCX, Extensions: []string{".docx"}, Extract: parseDocx},
+		{Format: FormatODT, Extensions: []string{".odt"}, Extract: TextExtractor(parseODT)},
+		{Format: FormatRTF, Extensions: []string{".rtf"}, Extract: TextExtractor(parseRTF)},
+		{Format: FormatHTML, Extensions: []string{".html", ".htm"}, Extract: parseHTML},
+		{Format: FormatMarkdown, Extensions: []string{".md", ".markdown"}, PlainText: true, Extract: TextExtractor(parseMarkdown)},
+		{Format: FormatEmail, Extensions: []string{".eml", ".mbox"}, PlainText: true, Extract: parseEmail},
+	} {
+		Register(spec)
+	}
+}
+
+// Register adds a format, replacing any earlier registration for the same
+// format or extensions. It is not safe to call concurrently with parsing and
+// is meant for package initialization.
+func Register(spec Spec) {
+	formats[spec.Format] = spec
+	for _, ext := range spec.Extensions {
+		extensions[strings.ToLower(ext)] = spec.Format
+	}
+}
+
+// resolve picks the format to parse with from the detected format and the
+// file extension. A known extension must agree with the content, except that
+// plain text formats such as Markdown are told apart by extension alone.
+func resolve(detected Format, ext string) (Spec, error) {
+	if expected, ok := extensions[ext]; ok && expected != detected {
+		if detected != FormatText || !formats[expected].PlainText {
+			return Spec{}, &MismatchError{Extension: ext, Expected: expected, Detected: detected}
+		}
+		detected = expected
+	}
+	spec, ok := formats[detected]
+	if !ok {
+		return Spec{}, &UnsupportedFormatError{Format: detected}
+	}
+	return spec, nil
+}
+
+// extractText returns plain text as UTF-8
+func extractText(data []byte) (string, error) {
+	return decodeText(data), nil
+}
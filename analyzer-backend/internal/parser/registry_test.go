@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		detected Format
+		ext      string
+		want     Format
+		wantErr  any
+	}{
+		{name: "matching extension", detected: FormatRTF, ext: ".rtf", want: FormatRTF},
+		{name: "unknown extension", detected: FormatODT, ext: ".bin", want: FormatODT},
+		{name: "markdown by extension", detected: FormatText, ext: ".md", want: FormatMarkdown},
+		{name: "text extension", detected: FormatText, ext: ".txt", want: FormatText},
+		{name: "html named as markdown", detected: FormatHTML, ext: ".md", wantErr: &MismatchError{}},
+		{name: "text named as rtf", detected: FormatText, ext: ".rtf", wantErr: &MismatchError{}},
+		{name: "legacy word", detected: FormatOLE, ext: ".doc", wantErr: &UnsupportedFormatError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := resolve(tt.detected, tt.ext)
+			switch want := tt.wantErr.(type) {
+			case *MismatchError:
+				if !errors.As(err, &want) {
+					t.Errorf("resolve() error = %v, want a MismatchError", err)
+				}
+			case *UnsupportedFormatError:
+				if !errors.As(err, &want) {
+					t.Errorf("resolve() error = %v, want an UnsupportedFormatError", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("resolve() error = %v", err)
+				}
+				if spec.Format != tt.want {
+					t.Errorf("resolve() format = %s, want %s", spec.Format, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// rtfDestinations are groups whose content is not document text
+var rtfDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true,
+	"pict": true, "object": true, "themedata": true, "datastore": true,
+	"listtable": true, "listoverridetable": true, "rsidtbl": true,
+	"generator": true, "xmlnstbl": true, "latentstyles": true,
+	"filetbl": true, "revtbl": true, "fldinst": true,
+	"header": true, "headerl": true, "headerr": true, "headerf": true,
+	"footer": true, "footerl": true, "footerr": true, "footerf": true,
+}
+
+// rtfSymbols are control words that stand for a character
+var rtfSymbols = map[string]string{
+	"par": "\n", "line": "\n", "row": "\n", "sect": "\n",
+	"page": "\f", "tab": "\t", "cell": "\t",
+	"emdash": "—", "endash": "–", "bullet": "•",
+	"lquote": "‘", "rquote": "’", "ldblquote": "“", "rdblquote": "”",
+	"emspace": " ", "enspace": " ",
+}
+
+// cp1252 maps the Windows-1252 bytes 0x80-0x9F that differ from Latin-1
+var cp1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// rtfState is the formatting state saved and restored with each group
+type rtfState struct {
+	skip      bool // Inside a destination that is not document text
+	ucSkip    int  // Fallback characters that follow a \u escape
+	firstWord bool // No control word seen yet in this group
+}
+
+// parseRTF extracts text from RTF by stripping control words. Unicode escapes
+// replace their fallback characters and hex escapes are read as Windows-1252.
+func parseRTF(data []byte) (string, error) {
+	var buf strings.Builder
+	var units []uint16 // Pending UTF-16 code units from \u escapes
+	flush := func() {
+		if len(units) > 0 {
+			buf.WriteString(string(utf16.Decode(units)))
+			units = units[:0]
+		}
+	}
+
+	state := rtfState{ucSkip: 1}
+	var stack []rtfState
+	pendingSkip := 0 // Fallback characters still to drop after a \u escape
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch c {
+		case '{':
+			stack = append(stack, state)
+			state.firstWord = true
+			pendingSkip = 0
+			i++
+			continue
+		case '}':
+			if len(stack) == 0 {
+				return "", fmt.Errorf("failed to parse RTF: unbalanced braces")
+			}
+			state = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			pendingSkip = 0
+			i++
+			continue
+		case '\r', '\n':
+			i++
+			continue
+		case '\\':
+		default:
+			if pendingSkip > 0 {
+				pendingSkip--
+			} else if !state.skip {
+				flush()
+				buf.WriteRune(decodeCP1252(c))
+			}
+			i++
+			continue
+		}
+
+		// Control symbol or control word
+		if i+1 >= len(data) {
+			break
+		}
+		next := data[i+1]
+		if !isASCIILetter(next) {
+			i += 2
+			var text string
+			switch next {
+			case '\\', '{', '}':
+				text = string(next)
+			case '~':
+				text = " "
+			case '_':
+				text = "-"
+			case '*':
+				// Optional destination that readers may ignore
+				state.skip = true
+			case '\'':
+				if i+2 <= len(data) {
+					if b, err := strconv.ParseUint(string(data[i:i+2]), 16, 8); err == nil {
+						text = string(decodeCP1252(byte(b)))
+					}
+					i += 2
+				}
+			case '\r', '\n':
+				text = "\n"
+			}
+			state.firstWord = false
+			if text == "" {
+				continue
+			}
+			if pendingSkip > 0 {
+				pendingSkip--
+			} else if !state.skip {
+				flush()
+				buf.WriteString(text)
+			}
+			continue
+		}
+
+		// Control word: letters, an optional signed number, an optional space
+		start := i + 1
+		j := start
+		for j < len(data) && isASCIILetter(data[j]) {
+			j++
+		}
+		word := string(data[start:j])
+		numStart := j
+		if j < len(data) && data[j] == '-' {
+			j++
+		}
+		for j < len(data) && data[j] >= '0' && data[j] <= '9' {
+			j++
+		}
+		param, hasParam := 0, false
+		if j > numStart {
+			param, _ = strconv.Atoi(string(data[numStart:j]))
+			hasParam = true
+		}
+		if j < len(data) && data[j] == ' ' {
+			j++
+		}
+		i = j
+
+		firstWord := state.firstWord
+		state.firstWord = false
+		if firstWord && rtfDestinations[word] {
+			state.skip = true
+		}
+
+		switch word {
+		case "bin":
+			// Binary data follows; skip it
+			i += max(param, 0)
+		case "uc":
+			if hasParam {
+				state.ucSkip = max(param, 0)
+			}
+		case "u":
+			if !state.skip {
+				if param < 0 {
+					param += 65536
+				}
+				units = append(units, uint16(param))
+			}
+			pendingSkip = state.ucSkip
+		default:
+			if text, ok := rtfSymbols[word]; ok && !state.skip {
+				flush()
+				buf.WriteString(text)
+			}
+		}
+	}
+	flush()
+
+	return buf.String(), nil
+}
+
+// decodeCP1252 decodes a Windows-1252 byte
+func decodeCP1252(b byte) rune {
+	if b >= 0x80 && b < 0xA0 {
+		return cp1252[b-0x80]
+	}
+	return rune(b)
+}
+
+func isASCIILetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
@@ -0,0 +1,43 @@
+package parser
+
+import "testing"
+
+func TestParseRTF(t *testing.T) {
+	tests := []struct {
+		name string
+		rtf  string
+		want string
+	}{
+		{"plain", `{\rtf1\ansi Offer of employment\par}`, "Offer of employment\n"},
+		{"unicode with default fallback", `{\rtf1 Caf\u233?}`, "Café"},
+		{"unicode without fallback", `{\rtf1\uc0 \u8377 500}`, "₹500"},
+		{"unicode with longer fallback", `{\rtf1\uc3 \u8364 EUR100}`, "€100"},
+		{"negative code unit", `{\rtf1 \u-3913?}`, ""},
+		{"surrogate pair", `{\rtf1 \u-10179?\u-8701?}`, "😃"},
+		{"fallback as hex escape", `{\rtf1 \u8220\'93quoted\u8221\'94}`, "“quoted”"},
+		{"uc restored after group", `{\rtf1 {\uc0 \u8377}\u8377?}`, "₹₹"},
+		{"fallback cut short by group", `{\rtf1\uc3 {\u8377 x}y}`, "₹y"},
+		{"windows-1252 hex escape", `{\rtf1 \'93Notice\'94 \'85}`, "“Notice” …"},
+		{"escaped characters", `{\rtf1 a\{b\}c\\d\~e}`, `a{b}c\d e`},
+		{"skipped destinations", `{\rtf1{\fonttbl{\f0 Arial;}}{\*\generator Writer;}{\info{\title Secret}}Body}`, "Body"},
+		{"symbols", `{\rtf1 A\tab B\line C\emdash D\page E}`, "A\tB\nC—D\fE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRTF([]byte(tt.rtf))
+			if err != nil {
+				t.Fatalf("parseRTF() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseRTF() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRTFUnbalanced(t *testing.T) {
+	if _, err := parseRTF([]byte(`{\rtf1 Offer}}`)); err == nil {
+		t.Error("parseRTF() error = nil, want an error for unbalanced braces")
+	}
+}
@@ -0,0 +1,306 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"ea-scanner/internal/models"
+)
+
+// maxHeading is the longest paragraph read as a heading, in characters
+const maxHeading = 80
+
+// Numbering styles; a new marker closes the open clauses down to the last
+// one in the same style
+const (
+	styleDecimal  = "decimal"  // 1, 1.2, 1.2.3
+	styleNumber   = "number"   // (1)
+	styleLower    = "lower"    // (a), a)
+	styleUpper    = "upper"    // (A), A.
+	styleRoman    = "roman"    // (iv), iv.
+	styleRomanCap = "ROMAN"    // IV.
+	styleSection  = "section"  // Article 2, or an upper case heading
+	styleSchedule = "schedule" // Schedule 1, Annexure A
+	stylePreamble = "preamble"
+)
+
+var (
+	// decimalMarker matches "3.", "3)", "3.2" and "3.2.1."
+	decimalMarker = regexp.MustCompile(`^(\d{1,3}(?:\.\d{1,3})+\.?|\d{1,3}[.)])\s+(.*)$`)
+
+	// letterMarker matches "(a)", "a)", "A.", "(iv)", "iv." and "(1)"
+	letterMarker = regexp.MustCompile(`^(?:\(([a-zA-Z]|[ivxlcIVXLC]{2,6}|\d{1,3})\)|([a-zA-Z]|[ivxlcIVXLC]{2,6})[.)])\s+(.*)$`)
+
+	slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// segment is a clause being built and how it was numbered
+type segment struct {
+	clause *models.Clause
+	style  string
+	number string   // Decimal number or letter, as used in the ID
+	body   []string // Paragraphs of the clause itself
+}
+
+// segmenter builds a clause tree from paragraphs in document order
+type segmenter struct {
+	roots      []*models.Clause
+	open       []*segment // The current clause and its ancestors, outermost first
+	used       map[string]bool
+	structured bool // A numbered clause or heading has been seen
+}
+
+// Segment builds a tree of sections, clauses and schedules from normalized
+// paragraphs. Numbered headings, lettered and roman sub-clauses and schedule
+// headings open clauses; other paragraphs belong to the innermost open one.
+// Text before the first heading is a preamble.
+func Segment(paragraphs []Paragraph) []*models.Clause {
+	s := &segmenter{used: map[string]bool{}}
+	for _, p := range paragraphs {
+		s.add(p)
+	}
+	s.closeTo(0)
+	return s.roots
+}
+
+// add places one paragraph in the tree
+func (s *segmenter) add(p Paragraph) {
+	short := utf8.RuneCountInString(p.Text) <= maxHeading && !strings.Contains(p.Text, "\t")
+
+	if m := scheduleHeading.FindStringSubmatch(p.Text); m != nil && short && isTitle(m[3], true) {
+		s.closeTo(0)
+		id := strings.ToLower(m[1]) + "-" + strings.ToLower(m[2])
+		s.push(p, styleSchedule, m[2], models.ClauseSchedule, id, strings.TrimSpace(m[1]+" "+m[2]), m[3], "")
+		return
+	}
+	if m := sectionHeading.FindStringSubmatch(p.Text); m != nil && short && isTitle(m[3], true) {
+		s.closeTo(s.scheduleDepth())
+		id := s.scoped(strings.ToLower(m[1]) + "-" + strings.ToLower(m[2]))
+		s.push(p, styleSection, m[2], models.ClauseSection, id, m[1]+" "+m[2], m[3], "")
+		return
+	}
+	if m := decimalMarker.FindStringSubmatch(p.Text); m != nil {
+		number := strings.TrimRight(m[1], ".)")
+		s.closeTo(s.decimalParent(number))
+		title, body := "", m[2]
+		if short && isTitle(body, false) {
+			title, body = body, ""
+		}
+		kind := models.ClauseClause
+		if title != "" {
+			kind = models.ClauseSection
+		}
+		s.push(p, styleDecimal, number, kind, s.scoped(number), m[1], title, body)
+		return
+	}
+	if m := letterMarker.FindStringSubmatch(p.Text); m != nil && s.structured {
+		letter := m[1] + m[2]
+		style := s.letterStyle(letter)
+		s.closeTo(s.letterParent(style))
+		label := strings.TrimSpace(strings.TrimSuffix(p.Text, m[3]))
+		s.push(p, style, letter, models.ClauseClause, s.letterID(letter), label, "", m[3])
+		return
+	}
+	if s.structured && short && isCapsHeading(p.Text) {
+		s.closeTo(s.scheduleDepth())
+		id := s.scoped("section-" + slug(p.Text))
+		s.push(p, styleSection, "", models.ClauseSection, id, "", p.Text, "")
+		return
+	}
+
+	// Plain paragraph
+	if len(s.open) == 0 {
+		s.push(p, stylePreamble, "", models.ClausePreamble, "preamble", "", "", p.Text)
+		return
+	}
+	cur := s.open[len(s.open)-1]
+	cur.body = append(cur.body, p.Text)
+	s.extend(p)
+}
+
+// push opens a clause as a child of the innermost open clause
+func (s *segmenter) push(p Paragraph, style, number, kind, id, label, title, body string) {
+	if style != stylePreamble {
+		s.structured = true
+		if len(s.open) > 0 && s.open[0].style == stylePreamble {
+			s.closeTo(0)
+		}
+	}
+
+	c := &models.Clause{
+		ID:          s.unique(id),
+		Kind:        kind,
+		Label:       label,
+		Title:       strings.TrimSpace(title),
+		StartPage:   p.Page,
+		StartOffset: p.Offset,
+	}
+	if len(s.open) == 0 {
+		s.roots = append(s.roots, c)
+	} else {
+		parent := s.open[len(s.open)-1].clause
+		parent.Children = append(parent.Children, c)
+	}
+
+	seg := &segment{clause: c, style: style, number: number}
+	if body = strings.TrimSpace(body); body != "" {
+		seg.body = append(seg.body, body)
+	}
+	s.open = append(s.open, seg)
+	s.extend(p)
+}
+
+// extend stretches the open clauses to the end of p
+func (s *segmenter) extend(p Paragraph) {
+	end := p.Offset + utf8.RuneCountInString(p.Text)
+	for _, seg := range s.open {
+		seg.clause.EndOffset = end
+		seg.clause.EndPage = p.Page
+	}
+}
+
+// closeTo closes open clauses until depth remain
+func (s *segmenter) closeTo(depth int) {
+	for len(s.open) > depth {
+		seg := s.open[len(s.open)-1]
+		seg.clause.Body = strings.Join(seg.body, "\n\n")
+		s.open = s.open[:len(s.open)-1]
+	}
+}
+
+// scheduleDepth returns how many open clauses to keep when a section
+// heading starts: the enclosing schedule, if any
+func (s *segmenter) scheduleDepth() int {
+	if len(s.open) > 0 && s.open[0].style == styleSchedule {
+		return 1
+	}
+	return 0
+}
+
+// decimalParent returns how many open clauses to keep for a decimal number:
+// those up to the nearest clause whose number it extends, or the enclosing
+// section or schedule
+func (s *segmenter) decimalParent(number string) int {
+	for i := len(s.open) - 1; i >= 0; i-- {
+		seg := s.open[i]
+		switch seg.style {
+		case styleDecimal:
+			if strings.HasPrefix(number, seg.number+".") {
+				return i + 1
+			}
+		case styleSection, styleSchedule:
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// letterParent returns how many open clauses to keep for a marker of the
+// given style: a marker closes its previous sibling, or nests in the
+// current clause if the style is new
+func (s *segmenter) letterParent(style string) int {
+	for i := len(s.open) - 1; i >= 0; i-- {
+		switch s.open[i].style {
+		case style:
+			return i
+		case styleDecimal, styleSection, styleSchedule:
+			return len(s.open)
+		}
+	}
+	return len(s.open)
+}
+
+// letterStyle tells lettered from roman markers. Single letters that are
+// also roman numerals, such as (i) or (v), are read by the open sibling
+// they would follow.
+func (s *segmenter) letterStyle(marker string) string {
+	if marker[0] >= '0' && marker[0] <= '9' {
+		return styleNumber
+	}
+	upper := unicode.IsUpper(rune(marker[0]))
+	letter, roman := styleLower, styleRoman
+	if upper {
+		letter, roman = styleUpper, styleRomanCap
+	}
+	if !isRoman(marker) {
+		return letter
+	}
+	if len(marker) > 1 {
+		return roman
+	}
+
+	for i := len(s.open) - 1; i >= 0; i-- {
+		seg := s.open[i]
+		switch {
+		case seg.style == letter && len(seg.number) == 1 && seg.number[0]+1 == marker[0]:
+			return letter
+		case seg.style == roman && romanValue(seg.number)+1 == romanValue(marker):
+			return roman
+		}
+	}
+	if strings.EqualFold(marker, "i") {
+		return roman
+	}
+	return letter
+}
+
+// letterID returns the ID of a lettered clause, which extends its parent's
+func (s *segmenter) letterID(marker string) string {
+	if len(s.open) == 0 {
+		return strings.ToLower(marker)
+	}
+	return s.open[len(s.open)-1].clause.ID + "." + strings.ToLower(marker)
+}
+
+// scoped prefixes an ID with the enclosing schedule, whose numbering restarts
+func (s *segmenter) scoped(id string) string {
+	if len(s.open) > 0 && s.open[0].style == styleSchedule {
+		return s.open[0].clause.ID + "/" + id
+	}
+	return id
+}
+
+// unique makes an ID unique within the document by numbering repeats
+func (s *segmenter) unique(id string) string {
+	candidate := id
+	for n := 2; s.used[candidate]; n++ {
+		candidate = fmt.Sprintf("%s~%d", id, n)
+	}
+	s.used[candidate] = true
+	return candidate
+}
+
+func isRoman(s string) bool {
+	return strings.Trim(strings.ToLower(s), "ivxlc") == ""
+}
+
+// romanValue returns the value of a roman numeral, or 0 if it is malformed
+func romanValue(s string) int {
+	values := map[byte]int{'i': 1, 'v': 5, 'x': 10, 'l': 50, 'c': 100}
+	s = strings.ToLower(s)
+	total := 0
+	for i := 0; i < len(s); i++ {
+		v := values[s[i]]
+		if v == 0 {
+			return 0
+		}
+		if i+1 < len(s) && values[s[i+1]] > v {
+			total -= v
+		} else {
+			total += v
+		}
+	}
+	return total
+}
+
+// slug returns a lower case, hyphenated form of a heading for use in IDs
+func slug(text string) string {
+	s := strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(text), "-"), "-")
+	if len(s) > 40 {
+		s = strings.TrimRight(s[:40], "-")
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"ea-scanner/internal/models"
+)
+
+// clauseIDs lists the IDs of a clause tree depth first, indented by depth
+func clauseIDs(clauses []*models.Clause, depth int) []string {
+	var ids []string
+	for _, c := range clauses {
+		ids = append(ids, strings.Repeat("  ", depth)+c.ID)
+		ids = append(ids, clauseIDs(c.Children, depth+1)...)
+	}
+	return ids
+}
+
+// paragraphs numbers texts as consecutive paragraphs on page 1
+func paragraphs(texts ...string) []Paragraph {
+	var ps []Paragraph
+	offset := 0
+	for _, text := range texts {
+		ps = append(ps, Paragraph{Text: text, Page: 1, Offset: offset})
+		offset += len([]rune(text)) + 2
+	}
+	return ps
+}
+
+func TestSegmentClauseIDs(t *testing.T) {
+	tests := []struct {
+		name  string
+		texts []string
+		want  []string
+	}{
+		{
+			name:  "decimal numbering",
+			texts: []string{"1. Definitions", "1.1 Terms used here have their usual meaning.", "1.2 Headings are for convenience.", "2. Remuneration", "2.1 The salary is paid monthly."},
+			want:  []string{"1", "  1.1", "  1.2", "2", "  2.1"},
+		},
+		{
+			name:  "lettered and roman sub-clauses",
+			texts: []string{"3. Leave", "3.1 The Employee is entitled to:", "(a) annual leave;", "(i) of 20 days;", "(ii) taken in blocks;", "(b) sick leave."},
+			want:  []string{"3", "  3.1", "    3.1.a", "      3.1.a.i", "      3.1.a.ii", "    3.1.b"},
+		},
+		{
+			name:  "single letter roman numerals follow their siblings",
+			texts: []string{"4. Duties", "(h) attend meetings;", "(i) report to the manager;", "(j) keep records."},
+			want:  []string{"4", "  4.h", "  4.i", "  4.j"},
+		},
+		{
+			name:  "preamble and articles",
+			texts: []string{"This agreement is made on 1 May.", "Article IV - Termination", "Either party may end this agreement."},
+			want:  []string{"preamble", "article-iv"},
+		},
+		{
+			name:  "schedule numbering restarts",
+			texts: []string{"1. Salary", "1.1 The salary is set out below.", "Schedule 1 - Benefits", "1. Insurance", "1.1 Health cover is provided."},
+			want:  []string{"1", "  1.1", "schedule-1", "  schedule-1/1", "    schedule-1/1.1"},
+		},
+		{
+			name:  "upper case headings",
+			texts: []string{"1. The Employee is appointed as Analyst.", "CONFIDENTIALITY", "The Employee shall keep secrets."},
+			want:  []string{"1", "section-confidentiality"},
+		},
+		{
+			name:  "repeated numbers",
+			texts: []string{"1. Salary", "1. Bonus", "1. Leave"},
+			want:  []string{"1", "1~2", "1~3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clauseIDs(Segment(paragraphs(tt.texts...)), 0)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Segment() IDs =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestSegmentSpans(t *testing.T) {
+	clauses := Segment(paragraphs("1. Salary", "The salary is paid monthly.", "2. Leave"))
+	if len(clauses) != 2 {
+		t.Fatalf("Segment() returned %d clauses, want 2", len(clauses))
+	}
+	first := clauses[0]
+	if first.Title != "Salary" || first.Body != "The salary is paid monthly." {
+		t.Errorf("first clause title = %q, body = %q", first.Title, first.Body)
+	}
+	if first.StartOffset != 0 || first.EndOffset != 11+len("The salary is paid monthly.") {
+		t.Errorf("first clause spans %d-%d", first.StartOffset, first.EndOffset)
+	}
+	if clauses[1].StartOffset != first.EndOffset+2 {
+		t.Errorf("second clause starts at %d, want %d", clauses[1].StartOffset, first.EndOffset+2)
+	}
+}
@@ -0,0 +1,221 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Format is a document format detected from content
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatMarkdown Format = "markdown"
+	FormatPDF      Format = "pdf"
+	FormatDOCX     Format = "docx"
+	FormatODT      Format = "odt"
+	FormatRTF      Format = "rtf"
+	FormatHTML     Format = "html"
+	FormatEmail    Format = "email"  // RFC 822 message or single-message mbox
+	FormatOLE      Format = "ole"    // Legacy Office compound file, e.g. .doc
+	FormatZIP      Format = "zip"    // ZIP archive that is not a supported document
+	FormatBinary   Format = "binary" // Anything else that is not text
+)
+
+// MismatchError is returned when a file's content does not match its extension
+type MismatchError struct {
+	Extension string // Lowercased extension, e.g. ".txt"
+	Expected  Format // Format implied by the extension
+	Detected  Format // Format detected from the content
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("file extension %s does not match its content: expected %s, detected %s", e.Extension, e.Expected, e.Detected)
+}
+
+// UnsupportedFormatError is returned for detected formats that cannot be parsed
+type UnsupportedFormatError struct {
+	Format Format
+}
+
+func (e *UnsupportedFormatError) Error() string {
+	return fmt.Sprintf("unsupported document format: %s", e.Format)
+}
+
+var (
+	pdfMagic  = []byte("%PDF-")
+	zipMagic  = []byte("PK\x03\x04")
+	rtfMagic  = []byte(`{\rtf`)
+	oleMagic  = []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")
+	utf8BOM   = []byte("\xEF\xBB\xBF")
+	utf16LE   = []byte("\xFF\xFE")
+	utf16BE   = []byte("\xFE\xFF")
+	htmlHeads = [][]byte{[]byte("<!doctype html"), []byte("<html"), []byte("<head"), []byte("<body")}
+)
+
+// sniffLimit bounds how much of a document is inspected for its format
+const sniffLimit = 8192
+
+// Sniff detects a document's format from its leading bytes
+func Sniff(data []byte) Format {
+	head := data[:min(len(data), sniffLimit)]
+
+	switch {
+	// PDF readers accept a header anywhere in the first kilobyte
+	case bytes.Contains(head[:min(len(head), 1024)], pdfMagic):
+		return FormatPDF
+	case bytes.HasPrefix(head, zipMagic):
+		return sniffZIP(data)
+	case bytes.HasPrefix(head, oleMagic):
+		return FormatOLE
+	case bytes.HasPrefix(head, utf16LE), bytes.HasPrefix(head, utf16BE):
+		return sniffText(decodeUTF16(head))
+	}
+
+	if isBinary(head) {
+		return FormatBinary
+	}
+	return sniffText(string(bytes.TrimPrefix(head, utf8BOM)))
+}
+
+// odtMimetype is the content of an OpenDocument text's mimetype entry
+const odtMimetype = "application/vnd.oasis.opendocument.text"
+
+// sniffZIP tells Office Open XML and OpenDocument files apart from other archives
+func sniffZIP(data []byte) Format {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return FormatBinary
+	}
+	var contentTypes, document, odt bool
+	for _, f := range archive.File {
+		switch f.Name {
+		case "[Content_Types].xml":
+			contentTypes = true
+		case "word/document.xml":
+			document = true
+		case "mimetype":
+			odt = readZipFile(f, len(odtMimetype)+1) == odtMimetype
+		}
+	}
+	switch {
+	case contentTypes && document:
+		return FormatDOCX
+	case odt:
+		return FormatODT
+	}
+	return FormatZIP
+}
+
+// maxZipPart caps how many bytes are decompressed from one archive entry, so
+// a small zip bomb cannot exhaust memory
+const maxZipPart = 64 << 20
+
+// openZipFile opens an archive entry for reading at most maxZipPart bytes.
+// Reading past the limit fails instead of returning truncated content.
+func openZipFile(f *zip.File) (io.ReadCloser, error) {
+	if f.UncompressedSize64 > maxZipPart {
+		return nil, fmt.Errorf("%s is larger than %d MB uncompressed", f.Name, maxZipPart>>20)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	// The recorded size may be forged, so the limit is enforced while reading
+	return &limitedZipFile{ReadCloser: rc, name: f.Name, r: io.LimitReader(rc, maxZipPart+1)}, nil
+}
+
+// limitedZipFile reads an archive entry up to maxZipPart bytes
+type limitedZipFile struct {
+	io.ReadCloser
+	name string
+	r    io.Reader
+	read int64
+}
+
+func (f *limitedZipFile) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	f.read += int64(n)
+	if f.read > maxZipPart {
+		return 0, fmt.Errorf("%s is larger than %d MB uncompressed", f.name, maxZipPart>>20)
+	}
+	return n, err
+}
+
+// readZipFile returns up to limit bytes of an archive entry, or "" if it
+// cannot be read
+func readZipFile(f *zip.File, limit int) string {
+	rc, err := openZipFile(f)
+	if err != nil {
+		return ""
+	}
+	defer rc.Close()
+	data, _ := io.ReadAll(io.LimitReader(rc, int64(limit)))
+	return strings.TrimSpace(string(data))
+}
+
+// sniffText classifies decoded text as RTF, email, HTML or plain text
+func sniffText(text string) Format {
+	trimmed := bytes.TrimLeft([]byte(text), " \t\r\n")
+	if bytes.HasPrefix(trimmed, rtfMagic) {
+		return FormatRTF
+	}
+	if looksLikeEmail(string(trimmed)) {
+		return FormatEmail
+	}
+	lower := bytes.ToLower(trimmed[:min(len(trimmed), 64)])
+	for _, head := range htmlHeads {
+		if bytes.HasPrefix(lower, head) {
+			return FormatHTML
+		}
+	}
+	return FormatText
+}
+
+// isBinary reports whether data looks like binary content: it contains a NUL
+// byte or more than 10% control characters other than whitespace
+func isBinary(data []byte) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+	control := 0
+	for _, b := range data {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' {
+			control++
+		}
+	}
+	return control*10 > len(data)
+}
+
+// decodeText returns data as UTF-8, decoding UTF-16 and dropping byte order marks
+func decodeText(data []byte) string {
+	if bytes.HasPrefix(data, utf16LE) || bytes.HasPrefix(data, utf16BE) {
+		return decodeUTF16(data)
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	return string(bytes.ToValidUTF8(data, []byte("�")))
+}
+
+// decodeUTF16 decodes UTF-16 text that starts with a byte order mark
+func decodeUTF16(data []byte) string {
+	bigEndian := bytes.HasPrefix(data, utf16BE)
+	data = data[2:]
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+		} else {
+			units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+		}
+	}
+	return string(utf16.Decode(units))
+}
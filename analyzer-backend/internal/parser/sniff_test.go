@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"testing"
+	"unicode/utf16"
+)
+
+// zipArchive builds an archive holding the given files in order
+func zipArchive(t *testing.T, files ...[2]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, f[1])
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// utf16LEText encodes s as UTF-16 with a little-endian byte order mark
+func utf16LEText(s string) []byte {
+	data := []byte{0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(s)) {
+		data = append(data, byte(u), byte(u>>8))
+	}
+	return data
+}
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want Format
+	}{
+		{"pdf", []byte("%PDF-1.7\n1 0 obj"), FormatPDF},
+		{"pdf after junk", append(bytes.Repeat([]byte("x"), 500), "%PDF-1.4"...), FormatPDF},
+		{"docx", zipArchive(t, [2]string{"[Content_Types].xml", "<Types/>"}, [2]string{"word/document.xml", "<w:document/>"}), FormatDOCX},
+		{"other zip", zipArchive(t, [2]string{"notes.txt", "hello"}), FormatZIP},
+		{"legacy word", []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1\x00\x00"), FormatOLE},
+		{"rtf", []byte(`{\rtf1\ansi Offer}`), FormatRTF},
+		{"utf-16 rtf", utf16LEText(`{\rtf1\ansi Offer}`), FormatRTF},
+		{"html", []byte("\n<!DOCTYPE html><html><body>Offer</body></html>"), FormatHTML},
+		{"utf-8 text with bom", []byte("\xEF\xBB\xBFOffer of employment"), FormatText},
+		{"binary", []byte("GIF89a\x01\x00\x01\x00\x00\x00"), FormatBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sniff(tt.data); got != tt.want {
+				t.Errorf("Sniff() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDocumentFormat(t *testing.T) {
+	tests := []struct {
+		name         string
+		filename     string
+		data         []byte
+		want         Format
+		wantMismatch bool
+		wantErr      error
+	}{
+		{name: "text", filename: "offer.txt", data: []byte("Offer of employment"), want: FormatText},
+		{name: "no extension", filename: "offer", data: []byte("Offer of employment"), want: FormatText},
+		{name: "pdf named as text", filename: "offer.txt", data: []byte("%PDF-1.7\n"), wantMismatch: true},
+		{name: "text named as pdf", filename: "offer.PDF", data: []byte("Offer of employment"), wantMismatch: true},
+		{name: "legacy word", filename: "offer.doc", data: []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1\x00\x00"), wantErr: &UnsupportedFormatError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ParseDocument(base64.StdEncoding.EncodeToString(tt.data), tt.filename)
+			var mismatch *MismatchError
+			var unsupported *UnsupportedFormatError
+			switch {
+			case tt.wantMismatch:
+				if !errors.As(err, &mismatch) {
+					t.Errorf("ParseDocument() error = %v, want a MismatchError", err)
+				}
+			case tt.wantErr != nil:
+				if !errors.As(err, &unsupported) {
+					t.Errorf("ParseDocument() error = %v, want an UnsupportedFormatError", err)
+				}
+			case err != nil:
+				t.Errorf("ParseDocument() error = %v", err)
+			case doc.Format != tt.want:
+				t.Errorf("ParseDocument() format = %s, want %s", doc.Format, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of personal data
+const (
+	KindName    = "NAME"
+	KindAddress = "ADDRESS"
+	KindEmail   = "EMAIL"
+	KindPhone   = "PHONE"
+	KindAccount = "ACCOUNT"
+	KindCard    = "CARD"
+	KindPAN     = "PAN"
+	KindAadhaar = "AADHAAR"
+	KindSSN     = "SSN"
+)
+
+// placeholderPattern matches placeholders inserted by Redact
+var placeholderPattern = regexp.MustCompile(`\[([A-Z]+)_(\d+)\]`)
+
+// PhonePatterns match candidate phone numbers, captured by the first group
+// and filtered by PhoneValid. A number must carry a country code or trunk
+// prefix, follow a common national layout or come after a phone label: bare
+// digit groups are more often references, dates or amounts.
+var PhonePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(\+\d{1,3}[\s.-]?(?:\(\d{1,5}\)[\s.-]?)?\d{1,5}(?:[\s.-]?\d{2,5}){1,4})`),
+	regexp.MustCompile(`(\(0\d{1,4}\)[\s.-]?\d{2,5}(?:[\s.-]?\d{2,5}){1,3}|\b0\d{1,4}[\s.-]?\d{2,5}(?:[\s.-]?\d{2,5}){1,3})`),
+	regexp.MustCompile(`(\(\d{3}\)[\s.-]?\d{3}[\s.-]\d{4}|\b\d{3}[.-]\d{3}[.-]\d{4})\b`),
+	regexp.MustCompile(`\b([6-9]\d{4}[\s-]?\d{5})\b`),
+	regexp.MustCompile(`(?i:\b(?:phone|ph|tel|telephone|mobile|mob|cell|whatsapp|contact)\b(?:\s*(?:no|number|#))?\.?\s*[:.-]?\s*)(\d{2,5}(?:[\s.-]?\d{2,5}){1,3})`),
+}
+
+// detector finds one kind of personal data. If the pattern has a capture
+// group, only the group is redacted. valid, if set, filters candidate matches.
+type detector struct {
+	kind    string
+	pattern *regexp.Regexp
+	valid   func(value string) bool
+}
+
+// detectors in priority order: when matches overlap, the earlier detector wins
+var detectors = []detector{
+	{KindEmail, regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), nil},
+	{KindSSN, regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), nil},
+	{KindPAN, regexp.MustCompile(`\b[A-Z]{3}[ABCFGHJLPT][A-Z]\d{4}[A-Z]\b`), nil},
+	{KindCard, regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), luhnValid},
+	{KindAccount, regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`), ibanValid},
+	{KindAadhaar, regexp.MustCompile(`\b[2-9]\d{3}[ -]\d{4}[ -]\d{4}\b`), verhoeffValid},
+	{KindAadhaar, regexp.MustCompile(`\b[2-9]\d{11}\b`), verhoeffValid},
+	{KindAccount, regexp.MustCompile(`(?i)\b(?:a/c|account|acct)\.?(?:\s*(?:no|number|num|#))?\.?\s*[:#-]?\s*(\d[\d -]{4,22}\d)\b`), nil},
+	{KindPhone, PhonePatterns[0], PhoneValid},
+	{KindPhone, PhonePatterns[1], PhoneValid},
+	{KindPhone, PhonePatterns[2], PhoneValid},
+	{KindPhone, PhonePatterns[3], PhoneValid},
+	{KindPhone, PhonePatterns[4], PhoneValid},
+	{KindName, regexp.MustCompile(`\b(?:Mr|Mrs|Ms|Miss|Mx|Dr|Shri|Smt|Sri|Kumari)\.?\s+([A-Z][a-z]+(?:\s+[A-Z]\.)?(?:\s+[A-Z][a-z]+){0,2})`), nil},
+	{KindName, regexp.MustCompile(`\b(?:Dear|Hi|Hello)\s+([A-Z][a-z]+(?:\s+[A-Z][a-z]+){0,2})\s*[,:]`), notSalutation},
+	// A bare "Name:" label counts only at the start of a line, so that
+	// "Company Name:" and "Bank Name:" keep the employer's identity
+	{KindName, regexp.MustCompile(`(?i:(?:(?:^|[\n\f])[ \t]*|\b(?:employee|candidate|applicant|full)\s+)name)[ \t]*:[ \t]*([A-Z][a-z]+(?:[ \t]+[A-Z][a-z]+){0,3})`), notSalutation},
+	{KindAddress, regexp.MustCompile(`(?i:\b(?:address|residing at|resident of))\s*[:,-]?\s*([A-Za-z0-9#][^;\f]{5,150}?(?:\b\d{3}\s?\d{3}\b|\b\d{5}(?:-\d{4})?\b|\.\s|;|$))`), nil},
+}
+
+// salutations are words greetings are addressed to that are not names
+var salutations = []string{"candidate", "applicant", "sir", "madam", "team", "hiring", "employee", "friend", "all", "customer", "user", "manager"}
+
+// Entity is a distinct redacted value
+type Entity struct {
+	Placeholder string // e.g. "[PHONE_1]"
+	Kind        string
+	Value       string // Original text
+	Count       int    // Occurrences replaced
+}
+
+// Result is a redacted text and the mapping needed to restore it
+type Result struct {
+	Text     string
+	Entities []*Entity
+
+	byPlaceholder map[string]*Entity
+}
+
+type span struct {
+	start, end int
+	kind       string
+}
+
+// Redact replaces personal data in text with stable placeholders. Repeated
+// values get the same placeholder; names found once are replaced everywhere.
+func Redact(text string) *Result {
+	var spans []span
+	for _, d := range detectors {
+		for _, loc := range d.pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			value := strings.TrimRight(text[start:end], " .,;")
+			end = start + len(value)
+			if value == "" || (d.valid != nil && !d.valid(value)) {
+				continue
+			}
+			spans = append(spans, span{start, end, d.kind})
+		}
+	}
+
+	// Replace every other occurrence of detected names, and of their first
+	// and last names on their own. Longer names go first so "John Smith"
+	// claims its occurrences before "John"; sorting also keeps placeholder
+	// numbering stable between runs.
+	seen := map[string]bool{}
+	var names []string
+	for _, s := range spans {
+		if s.kind != KindName {
+			continue
+		}
+		name := text[s.start:s.end]
+		for _, n := range append([]string{name}, nameParts(name)...) {
+			if !seen[n] {
+				seen[n] = true
+				names = append(names, n)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			spans = append(spans, span{loc[0], loc[1], KindName})
+		}
+	}
+
+	// Keep the earliest detected span where spans overlap
+	kept := make([]span, 0, len(spans))
+	for _, s := range spans {
+		overlaps := false
+		for _, k := range kept {
+			if s.start < k.end && k.start < s.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			kept = append(kept, s)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].start < kept[j].start })
+
+	result := &Result{byPlaceholder: map[string]*Entity{}}
+	byValue := map[string]*Entity{}
+	counts := map[string]int{}
+
+	var b strings.Builder
+	last := 0
+	for _, s := range kept {
+		value := text[s.start:s.end]
+		key := s.kind + "\x00" + value
+		entity, ok := byValue[key]
+		if !ok {
+			counts[s.kind]++
+			entity = &Entity{
+				Placeholder: fmt.Sprintf("[%s_%d]", s.kind, counts[s.kind]),
+				Kind:        s.kind,
+				Value:       value,
+			}
+			byValue[key] = entity
+			result.byPlaceholder[entity.Placeholder] = entity
+			result.Entities = append(result.Entities, entity)
+		}
+		entity.Count++
+
+		b.WriteString(text[last:s.start])
+		b.WriteString(entity.Placeholder)
+		last = s.end
+	}
+	b.WriteString(text[last:])
+	result.Text = b.String()
+
+	return result
+}
+
+// Mask replaces personal data in text with unnumbered placeholders such as
+// [EMAIL], for text sent alongside a redacted document whose numbered
+// placeholders it must not be confused with. Masked text cannot be restored.
+func Mask(text string) string {
+	return placeholderPattern.ReplaceAllString(Redact(text).Text, "[$1]")
+}
+
+// Restore replaces placeholders in s with the original values
+func (r *Result) Restore(s string) string {
+	if len(r.byPlaceholder) == 0 || !strings.Contains(s, "[") {
+		return s
+	}
+	return placeholderPattern.ReplaceAllStringFunc(s, func(p string) string {
+		if entity, ok := r.byPlaceholder[p]; ok {
+			return entity.Value
+		}
+		return p
+	})
+}
+
+// Counts returns the number of distinct redacted values per kind
+func (r *Result) Counts() map[string]int {
+	counts := map[string]int{}
+	for _, e := range r.Entities {
+		counts[e.Kind]++
+	}
+	return counts
+}
+
+// nameParts returns the first and last tokens of a multi-word name, skipping
+// initials
+func nameParts(name string) []string {
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return nil
+	}
+	var parts []string
+	for _, f := range []string{fields[0], fields[len(fields)-1]} {
+		if len(f) > 1 && !strings.HasSuffix(f, ".") {
+			parts = append(parts, f)
+		}
+	}
+	return parts
+}
+
+func notSalutation(value string) bool {
+	first := strings.ToLower(strings.Fields(value)[0])
+	for _, s := range salutations {
+		if first == s {
+			return false
+		}
+	}
+	return true
+}
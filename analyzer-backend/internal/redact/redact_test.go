@@ -0,0 +1,93 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		want     string
+		unwanted []string // Values that must not survive redaction
+	}{
+		{
+			name: "spaced aadhaar with valid checksum",
+			text: "Aadhaar 2345 6789 0124 on file.",
+			want: "Aadhaar [AADHAAR_1] on file.",
+		},
+		{
+			name:     "spaced reference number is not aadhaar",
+			text:     "Purchase order 4500 1234 5678 is attached.",
+			unwanted: []string{"[AADHAAR_"},
+		},
+		{
+			name: "first and last names on their own",
+			text: "This offer is made to Mr. John Smith. John will report to the CTO. Smith must sign below.",
+			want: "This offer is made to Mr. [NAME_1]. [NAME_2] will report to the CTO. [NAME_3] must sign below.",
+		},
+		{
+			name: "phone numbers",
+			text: "Call +91 98765 43210, 020 7946 0958, (415) 555-2671 or mobile: 98765 43210.",
+			want: "Call [PHONE_1], [PHONE_2], [PHONE_3] or mobile: [PHONE_4].",
+		},
+		{
+			name: "reference number is not a phone number",
+			text: "Our reference 2024 0115 7788 applies.",
+			want: "Our reference 2024 0115 7788 applies.",
+		},
+		{
+			name: "employee name label",
+			text: "Employee Name: Rahul Verma\nDesignation: Analyst",
+			want: "Employee Name: [NAME_1]\nDesignation: Analyst",
+		},
+		{
+			name: "name label on its own line",
+			text: "Offer of Employment\nName: Priya Sharma\nRole: Engineer",
+			want: "Offer of Employment\nName: [NAME_1]\nRole: Engineer",
+		},
+		{
+			name: "company name label",
+			text: "Company Name: Acme Technologies Private Limited",
+			want: "Company Name: Acme Technologies Private Limited",
+		},
+		{
+			name: "bank name label",
+			text: "Bank Name: State Bank of India",
+			want: "Bank Name: State Bank of India",
+		},
+		{
+			name:     "initials are not redacted on their own",
+			text:     "Dear Ms. Priya K. Sharma, welcome. Priya starts Monday with K. Rao.",
+			unwanted: []string{"Priya", "Sharma"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Redact(tt.text)
+			if tt.want != "" && got.Text != tt.want {
+				t.Errorf("Redact() = %q, want %q", got.Text, tt.want)
+			}
+			for _, value := range tt.unwanted {
+				if strings.Contains(got.Text, value) {
+					t.Errorf("Redact() = %q, contains %q", got.Text, value)
+				}
+			}
+			if restored := got.Restore(got.Text); restored != tt.text {
+				t.Errorf("Restore() = %q, want the original text", restored)
+			}
+		})
+	}
+}
+
+func TestRedactStablePlaceholders(t *testing.T) {
+	text := "Mr. Arjun Mehta and Ms. Neha Kapoor met Dr. Ravi Iyer. Later Arjun, Neha and Ravi signed; Mehta, Kapoor and Iyer witnessed."
+	first := Redact(text).Text
+	for range 20 {
+		if got := Redact(text).Text; got != first {
+			t.Fatalf("Redact() = %q, want the same placeholders as %q", got, first)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package rules
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed packs/builtin.json
+var builtinPack []byte
+
+//go:embed packs/jurisdictions/*.json
+var jurisdictionFS embed.FS
+
+// ErrUnknownJurisdiction is returned for a jurisdiction without a rule pack
+var ErrUnknownJurisdiction = errors.New("unknown jurisdiction")
+
+// Rule kinds
+const (
+	KindScam   = "scam"   // Reported as a scam indicator
+	KindClause = "clause" // Reported as a risky clause
+)
+
+// Contract terms a TermCheck can test
+const (
+	TermNoticePeriod    = "notice_period"    // Days
+	TermProbationPeriod = "probation_period" // Days
+	TermNonCompete      = "non_compete"      // Days; a non-compete without a stated duration counts as 1
+	TermHoursPerWeek    = "hours_per_week"   // Hours
+)
+
+// maxHitsPerRule caps how many matches a single rule reports
+const maxHitsPerRule = 3
+
+// Rule is a declarative indicator matched against document text
+type Rule struct {
+	ID          string   `json:"id"`
+	Category    string   `json:"category"`
+	Severity    string   `json:"severity"` // LOW/MEDIUM/HIGH/CRITICAL
+	Explanation string   `json:"explanation"`
+	Patterns    []string `json:"patterns,omitempty"` // Regular expressions, matched case-insensitively
+	Keywords    []string `json:"keywords,omitempty"` // Phrases, matched case-insensitively on word boundaries
+	Kind        string   `json:"kind,omitempty"`     // scam (default) or clause
+	Absent      bool     `json:"absent,omitempty"`   // Rule fires when none of its patterns match
+
+	compiled []*regexp.Regexp
+}
+
+// TermCheck is a deterministic limit on an extracted contract term
+type TermCheck struct {
+	ID          string   `json:"id"`
+	Term        string   `json:"term"` // One of the Term* constants
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Category    string   `json:"category"`
+	Severity    string   `json:"severity"` // LOW/MEDIUM/HIGH/CRITICAL
+	Explanation string   `json:"explanation"`
+}
+
+// Pack is a named set of rules
+type Pack struct {
+	Name         string       `json:"name"`
+	Jurisdiction string       `json:"jurisdiction,omitempty"` // Jurisdiction code, e.g. "IN" or "US-CA"
+	Prompt       string       `json:"prompt,omitempty"`       // Addendum to the analysis prompt
+	Rules        []*Rule      `json:"rules"`
+	TermChecks   []*TermCheck `json:"term_checks,omitempty"`
+}
+
+// Hit is a single rule match. Hits of absent rules have no quote and a
+// Start of -1.
+type Hit struct {
+	Rule  *Rule
+	Pack  *Pack
+	Quote string // Matched text
+	Start int    // Byte offset of the match
+	End   int    // Byte offset just past the match
+}
+
+// LoadPack parses and compiles a JSON rule pack
+func LoadPack(data []byte) (*Pack, error) {
+	var pack Pack
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return nil, fmt.Errorf("invalid rule pack: %w", err)
+	}
+
+	ids := map[string]bool{}
+	for _, rule := range pack.Rules {
+		// Findings are matched to their rule by ID
+		if rule.ID == "" {
+			return nil, fmt.Errorf("rule in pack %s has no id", pack.Name)
+		}
+		if ids[rule.ID] {
+			return nil, fmt.Errorf("duplicate rule id %s in pack %s", rule.ID, pack.Name)
+		}
+		ids[rule.ID] = true
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("rule %s in pack %s: %w", rule.ID, pack.Name, err)
+		}
+	}
+	for _, check := range pack.TermChecks {
+		if err := check.validate(); err != nil {
+			return nil, fmt.Errorf("term check %s in pack %s: %w", check.ID, pack.Name, err)
+		}
+	}
+
+	return &pack, nil
+}
+
+// builtin and jurisdictions are loaded and compiled once; packs are
+// read-only after loading, so scanners share them
+var (
+	builtin       = mustLoadPack(builtinPack)
+	jurisdictions = mustLoadJurisdictions()
+)
+
+// mustLoadPack loads an embedded rule pack, panicking if it is invalid
+func mustLoadPack(data []byte) *Pack {
+	pack, err := LoadPack(data)
+	if err != nil {
+		panic(err)
+	}
+	return pack
+}
+
+// mustLoadJurisdictions loads the embedded jurisdiction packs by code
+func mustLoadJurisdictions() map[string]*Pack {
+	entries, err := jurisdictionFS.ReadDir("packs/jurisdictions")
+	if err != nil {
+		panic(err)
+	}
+	packs := make(map[string]*Pack, len(entries))
+	for _, entry := range entries {
+		data, err := jurisdictionFS.ReadFile(path.Join("packs/jurisdictions", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		packs[strings.TrimSuffix(entry.Name(), ".json")] = mustLoadPack(data)
+	}
+	return packs
+}
+
+// Builtin returns the built-in scam indicator rule pack
+func Builtin() *Pack {
+	return builtin
+}
+
+// Jurisdiction returns the rule pack for a jurisdiction code
+func Jurisdiction(code string) (*Pack, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	pack, ok := jurisdictions[code]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (supported: %s)", ErrUnknownJurisdiction, code, strings.Join(Jurisdictions(), ", "))
+	}
+	return pack, nil
+}
+
+// Jurisdictions returns the supported jurisdiction codes, sorted
+func Jurisdictions() []string {
+	codes := make([]string, 0, len(jurisdictions))
+	for code := range jurisdictions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// compile prepares the rule's regular expressions
+func (r *Rule) compile() error {
+	if err := validSeverity(r.Severity); err != nil {
+		return err
+	}
+	switch r.Kind {
+	case "":
+		r.Kind = KindScam
+	case KindScam, KindClause:
+	default:
+		return fmt.Errorf("invalid kind %q", r.Kind)
+	}
+
+	r.compiled = nil
+	for _, pattern := range r.Patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		r.compiled = append(r.compiled, re)
+	}
+
+	if len(r.Keywords) > 0 {
+		quoted := make([]string, len(r.Keywords))
+		for i, kw := range r.Keywords {
+			quoted[i] = strings.ReplaceAll(regexp.QuoteMeta(kw), " ", `\s+`)
+		}
+		r.compiled = append(r.compiled, regexp.MustCompile(`(?i)\b(?:`+strings.Join(quoted, "|")+`)\b`))
+	}
+
+	if len(r.compiled) == 0 {
+		return fmt.Errorf("rule has no patterns or keywords")
+	}
+	return nil
+}
+
+// validate checks the term check's definition
+func (c *TermCheck) validate() error {
+	if err := validSeverity(c.Severity); err != nil {
+		return err
+	}
+	switch c.Term {
+	case TermNoticePeriod, TermProbationPeriod, TermNonCompete, TermHoursPerWeek:
+	default:
+		return fmt.Errorf("invalid term %q", c.Term)
+	}
+	if c.Min == nil && c.Max == nil {
+		return fmt.Errorf("term check has no min or max")
+	}
+	return nil
+}
+
+func validSeverity(severity string) error {
+	switch severity {
+	case "LOW", "MEDIUM", "HIGH", "CRITICAL":
+		return nil
+	}
+	return fmt.Errorf("invalid severity %q", severity)
+}
+
+// Scanner matches rule packs against text
+type Scanner struct {
+	packs []*Pack
+}
+
+// NewScanner creates a Scanner for the given packs
+func NewScanner(packs ...*Pack) *Scanner {
+	return &Scanner{packs: packs}
+}
+
+// Scan returns all rule hits in text, ordered by position
+func (s *Scanner) Scan(text string) []Hit {
+	var hits []Hit
+
+	for _, pack := range s.packs {
+		for _, rule := range pack.Rules {
+			matches := rule.match(text)
+			if rule.Absent {
+				if len(matches) == 0 {
+					hits = append(hits, Hit{Rule: rule, Pack: pack, Start: -1, End: -1})
+				}
+				continue
+			}
+			for _, hit := range matches {
+				hit.Pack = pack
+				hits = append(hits, hit)
+			}
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Start < hits[j].Start
+	})
+	return hits
+}
+
+// match returns up to maxHitsPerRule non-overlapping hits for the rule
+func (r *Rule) match(text string) []Hit {
+	var hits []Hit
+
+	for _, re := range r.compiled {
+		for _, loc := range re.FindAllStringIndex(text, maxHitsPerRule) {
+			if overlapsAny(hits, loc[0], loc[1]) {
+				continue
+			}
+			hits = append(hits, Hit{Rule: r, Quote: text[loc[0]:loc[1]], Start: loc[0], End: loc[1]})
+			if len(hits) == maxHitsPerRule {
+				return hits
+			}
+		}
+	}
+
+	return hits
+}
+
+func overlapsAny(hits []Hit, start, end int) bool {
+	for _, h := range hits {
+		if start < h.End && h.Start < end {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package rules
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// ruleIDs returns the IDs of the rules hit, in order
+func ruleIDs(hits []Hit) []string {
+	var ids []string
+	for _, hit := range hits {
+		ids = append(ids, hit.Rule.ID)
+	}
+	return ids
+}
+
+func TestBuiltinRules(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "training fee",
+			text: "Selected candidates must pay a refundable training fee of Rs 5,000.",
+			want: []string{"FEE-UPFRONT"},
+		},
+		{
+			name: "gift cards",
+			text: "Buy Gift  Cards for the office and send us the codes.",
+			want: []string{"UNTRACEABLE-PAYMENT"},
+		},
+		{
+			name: "free-mail hiring address",
+			text: "Send your documents to hr.department2024@gmail.com today.",
+			want: []string{"FREEMAIL-RECRUITER"},
+		},
+		{
+			name: "free-mail address after a recruiter label",
+			text: "Recruiter: Anita Desai, anita.desai@yahoo.co.in",
+			want: []string{"FREEMAIL-RECRUITER"},
+		},
+		{
+			name: "candidate's own free-mail address",
+			text: "Priya Sharma\n12 Lake Road, Pune\npriya.sharma@gmail.com\n\nDear Priya, we are pleased to offer you the role.",
+		},
+		{
+			name: "hiring word inside another word",
+			text: "Shraddha Rao, shraddha.rao@gmail.com",
+		},
+		{
+			name: "company address",
+			text: "Questions can be sent to careers@acme.com.",
+		},
+		{
+			name: "chat app interview",
+			text: "Your interview will be conducted via Telegram with our HR manager.",
+			want: []string{"CHAT-APP-INTERVIEW"},
+		},
+		{
+			name: "ordinary offer",
+			text: "Your annual salary will be paid monthly. Please sign and return this letter by 30 June.",
+		},
+	}
+
+	scanner := NewScanner(Builtin())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ruleIDs(scanner.Scan(tt.text)); !slices.Equal(got, tt.want) {
+				t.Errorf("Scan() rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanHitOffsets(t *testing.T) {
+	text := "Pay the registration fee. Then pay the visa charges. A joining fee, a training deposit and an uniform cost follow."
+	hits := NewScanner(Builtin()).Scan(text)
+
+	if len(hits) != maxHitsPerRule {
+		t.Fatalf("Scan() = %d hits, want %d", len(hits), maxHitsPerRule)
+	}
+	for i, hit := range hits {
+		if text[hit.Start:hit.End] != hit.Quote {
+			t.Errorf("hit %d quote = %q, text at offsets = %q", i, hit.Quote, text[hit.Start:hit.End])
+		}
+		if i > 0 && hit.Start < hits[i-1].End {
+			t.Errorf("hit %d overlaps hit %d", i, i-1)
+		}
+	}
+}
+
+func TestJurisdictionRules(t *testing.T) {
+	tests := []struct {
+		name         string
+		jurisdiction string
+		text         string
+		want         []string
+	}{
+		{
+			name:         "absent rule fires without its keyword",
+			jurisdiction: "IN",
+			text:         "The Employee shall serve a notice period of one month.",
+			want:         []string{"IN-GRATUITY"},
+		},
+		{
+			name:         "absent rule silent with its keyword",
+			jurisdiction: "in",
+			text:         "Gratuity is payable as per the Payment of Gratuity Act.",
+		},
+		{
+			name:         "clause pattern",
+			jurisdiction: "IN",
+			text:         "The Employee signs a training bond and gratuity applies as per law.",
+			want:         []string{"IN-SERVICE-BOND"},
+		},
+		{
+			name:         "out-of-state governing law",
+			jurisdiction: "US-CA",
+			text:         "This Agreement is governed by the laws of the State of Delaware.",
+			want:         []string{"US-CA-VENUE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pack, err := Jurisdiction(tt.jurisdiction)
+			if err != nil {
+				t.Fatalf("Jurisdiction() error = %v", err)
+			}
+			hits := NewScanner(pack).Scan(tt.text)
+			if got := ruleIDs(hits); !slices.Equal(got, tt.want) {
+				t.Fatalf("Scan() rules = %v, want %v", got, tt.want)
+			}
+			for _, hit := range hits {
+				if hit.Pack != pack || (hit.Rule.Absent && (hit.Start != -1 || hit.Quote != "")) {
+					t.Errorf("hit = %+v, want the pack set and no quote for absent rules", hit)
+				}
+			}
+		})
+	}
+
+	if _, err := Jurisdiction("XX"); !errors.Is(err, ErrUnknownJurisdiction) {
+		t.Errorf("Jurisdiction(XX) error = %v, want ErrUnknownJurisdiction", err)
+	}
+}
+
+func TestLoadPack(t *testing.T) {
+	tests := []struct {
+		name    string
+		pack    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			pack: `{"name":"test","rules":[{"id":"A","category":"C","severity":"LOW","keywords":["bond"]}],"term_checks":[{"id":"B","term":"notice_period","max":30,"category":"C","severity":"LOW"}]}`,
+		},
+		{
+			name:    "rule without id",
+			pack:    `{"name":"test","rules":[{"category":"C","severity":"LOW","keywords":["bond"]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate rule id",
+			pack:    `{"name":"test","rules":[{"id":"A","category":"C","severity":"LOW","keywords":["a"]},{"id":"A","category":"C","severity":"LOW","keywords":["b"]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			pack:    `{"name":"test","rules":[{"id":"A","category":"C","severity":"LOW","patterns":["("]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid severity",
+			pack:    `{"name":"test","rules":[{"id":"A","category":"C","severity":"SEVERE","keywords":["a"]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "rule without patterns",
+			pack:    `{"name":"test","rules":[{"id":"A","category":"C","severity":"LOW"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "term check without limits",
+			pack:    `{"name":"test","rules":[],"term_checks":[{"id":"B","term":"notice_period","category":"C","severity":"LOW"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown term",
+			pack:    `{"name":"test","rules":[],"term_checks":[{"id":"B","term":"salary","max":1,"category":"C","severity":"LOW"}]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadPack([]byte(tt.pack)); (err != nil) != tt.wantErr {
+				t.Errorf("LoadPack() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
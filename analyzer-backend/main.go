@@ -1,11 +1,23 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log"
+	"maps"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"ea-scanner/internal/analyzer"
 	"ea-scanner/internal/api"
+	"ea-scanner/internal/cache"
+	"ea-scanner/internal/jobs"
 )
 
 func main() {
@@ -14,8 +26,25 @@ func main() {
 		port = "8080"
 	}
 
+	// Select LLM provider
+	provider, err := newProvider()
+	if err != nil {
+		log.Fatalf("Failed to configure LLM provider: %v", err)
+	}
+
+	// Start async job workers
+	jobStore, err := newJobStore()
+	if err != nil {
+		log.Fatalf("Failed to open job store: %v", err)
+	}
+	jobManager := jobs.NewManager(jobStore, newJobConfig())
+
 	// Create handler
-	handler := api.NewHandler()
+	config := newConfig(provider)
+	if config.Cache, err = newCache(); err != nil {
+		log.Fatalf("Failed to open result cache: %v", err)
+	}
+	handler := api.NewHandler(provider, config, jobManager)
 
 	// Setup routes
 	mux := http.NewServeMux()
@@ -24,11 +53,183 @@ func main() {
 	// Start server
 	addr := ":" + port
 	log.Printf("🔍 Employment Agreement Scanner API starting on http://localhost%s", addr)
+	log.Printf("🤖 LLM provider: %s", provider.Name())
 	log.Printf("📋 Endpoints:")
-	log.Printf("   POST /api/analyze - Analyze employment agreement")
-	log.Printf("   GET  /health      - Health check")
+	log.Printf("   POST   /api/analyze         - Analyze employment agreement")
+	log.Printf("   POST   /api/analyze/stream  - Analyze with SSE progress")
+	log.Printf("   POST   /api/analyze/compare - Compare two agreement versions")
+	log.Printf("   POST   /api/resume/analyze  - Analyze resume")
+	log.Printf("   POST   /api/jobs            - Queue an analysis job")
+	log.Printf("   GET    /api/jobs/{id}       - Job status and result")
+	log.Printf("   DELETE /api/jobs/{id}       - Cancel a job")
+	log.Printf("   GET    /api/metrics         - Token usage and cost totals")
+	log.Printf("   GET    /api/models          - Models clients may request")
+	log.Printf("   GET    /health              - Health check")
+
+	// Serve until SIGINT or SIGTERM, then drain requests and stop the job
+	// workers; log.Fatalf would skip the cleanup
+	server := &http.Server{Addr: addr, Handler: mux}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	var failed bool
+	select {
+	case err := <-serveErr:
+		log.Printf("Server failed: %v", err)
+		failed = true
+	case <-ctx.Done():
+		log.Printf("Shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Server shutdown failed: %v", err)
+		}
+		cancel()
+	}
+	// Let running and queued jobs finish before canceling them
+	closeCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := jobManager.Close(closeCtx); err != nil {
+		log.Printf("Failed to close job store: %v", err)
+	}
+	cancel()
+	if failed {
+		os.Exit(1)
+	}
+}
 
-	if err := http.ListenAndServe(addr, mux); err != nil {
-		log.Fatalf("Server failed: %v", err)
+// newProvider builds the LLM provider selected by LLM_PROVIDER (default "gemini").
+// The "openai" provider needs OPENAI_BASE_URL and OPENAI_MODEL.
+func newProvider() (analyzer.Provider, error) {
+	switch os.Getenv("LLM_PROVIDER") {
+	case "", "gemini":
+		return analyzer.NewGeminiProvider(), nil
+	case "openai":
+		return analyzer.NewOpenAIProvider(analyzer.OpenAIConfig{
+			BaseURL: os.Getenv("OPENAI_BASE_URL"),
+			APIKey:  os.Getenv("OPENAI_API_KEY"),
+			Model:   os.Getenv("OPENAI_MODEL"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown LLM_PROVIDER %q (expected \"gemini\" or \"openai\")", os.Getenv("LLM_PROVIDER"))
+	}
+}
+
+// newConfig builds the analyzer configuration for the provider, applying
+// environment overrides
+func newConfig(provider analyzer.Provider) analyzer.Config {
+	config := analyzer.DefaultConfig()
+	if provider.Name() == "openai" {
+		// Self-hosted servers offer OPENAI_MODEL, plus any LLM_ALLOWED_MODELS,
+		// instead of the Gemini catalog
+		model := os.Getenv("OPENAI_MODEL")
+		config.Model, config.ResumeModel = model, model
+		config.AllowedModels = nil
+	}
+	config.Retry.MaxRepairs = envInt("LLM_MAX_REPAIRS", config.Retry.MaxRepairs)
+	config.Retry.MaxRetries = envInt("LLM_MAX_RETRIES", config.Retry.MaxRetries)
+	config.Retry.BaseDelay = envDuration("LLM_RETRY_BASE_DELAY", config.Retry.BaseDelay)
+	config.Retry.MaxDelay = envDuration("LLM_RETRY_MAX_DELAY", config.Retry.MaxDelay)
+	config.DropUnverified = os.Getenv("DROP_UNVERIFIED_FINDINGS") == "true"
+	config.ChunkSize = envInt("ANALYSIS_CHUNK_SIZE", config.ChunkSize)
+	config.ChunkOverlap = envInt("ANALYSIS_CHUNK_OVERLAP", config.ChunkOverlap)
+	config.Concurrency = envInt("ANALYSIS_CONCURRENCY", config.Concurrency)
+	config.Redact = os.Getenv("REDACT_PII") != "false"
+	config.CacheTTL = envDuration("CACHE_TTL", config.CacheTTL)
+
+	// LLM_PRICES overrides or extends the price table, e.g.
+	// {"gemini-2.0-flash": {"input": 0.10, "output": 0.40}} (USD per million tokens)
+	if value := os.Getenv("LLM_PRICES"); value != "" {
+		var prices map[string]analyzer.Price
+		if err := json.Unmarshal([]byte(value), &prices); err != nil {
+			log.Fatalf("Invalid LLM_PRICES: %v", err)
+		}
+		maps.Copy(config.Prices, prices)
+	}
+
+	// LLM_ALLOWED_MODELS replaces the models clients may request, e.g.
+	// "gemini-2.0-flash,gemini-2.5-flash"; the defaults stay allowed
+	if value := os.Getenv("LLM_ALLOWED_MODELS"); value != "" {
+		config.AllowedModels = nil
+		for _, model := range strings.Split(value, ",") {
+			if model = strings.TrimSpace(model); model != "" {
+				config.AllowedModels = append(config.AllowedModels, model)
+			}
+		}
+	}
+	return config
+}
+
+// newJobStore opens the job store selected by JOB_STORE (default "memory")
+func newJobStore() (jobs.Store, error) {
+	switch os.Getenv("JOB_STORE") {
+	case "", "memory":
+		return jobs.NewMemoryStore(), nil
+	case "sqlite":
+		path := os.Getenv("JOB_SQLITE_PATH")
+		if path == "" {
+			path = "jobs.db"
+		}
+		return jobs.NewSQLiteStore(path)
+	default:
+		return nil, fmt.Errorf("unknown JOB_STORE %q (expected \"memory\" or \"sqlite\")", os.Getenv("JOB_STORE"))
+	}
+}
+
+// newCache opens the result cache selected by CACHE_STORE (default "memory");
+// "none" disables caching
+func newCache() (cache.Store, error) {
+	switch os.Getenv("CACHE_STORE") {
+	case "", "memory":
+		return cache.NewMemoryStore(envInt("CACHE_SIZE", 1000)), nil
+	case "sqlite":
+		path := os.Getenv("CACHE_SQLITE_PATH")
+		if path == "" {
+			path = "cache.db"
+		}
+		return cache.NewSQLiteStore(path)
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown CACHE_STORE %q (expected \"memory\", \"sqlite\" or \"none\")", os.Getenv("CACHE_STORE"))
+	}
+}
+
+// newJobConfig builds the job worker configuration, applying environment overrides
+func newJobConfig() jobs.Config {
+	config := jobs.DefaultConfig()
+	config.Workers = envInt("JOB_WORKERS", config.Workers)
+	config.QueueDepth = envInt("JOB_QUEUE_DEPTH", config.QueueDepth)
+	config.Timeout = envDuration("JOB_TIMEOUT", config.Timeout)
+	config.Retention = envDuration("JOB_RETENTION", config.Retention)
+	return config
+}
+
+// envInt reads an integer environment variable, falling back to def
+func envInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return n
+}
+
+// envDuration reads a duration environment variable (e.g. "500ms"), falling back to def
+func envDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
 	}
+	return d
 }
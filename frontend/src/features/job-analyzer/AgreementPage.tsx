@@ -3,7 +3,7 @@ import type { DragEvent } from 'react';
 import { useNavigate } from 'react-router-dom';
 import './AgreementPage.css';
 import type { AnalysisResult } from './types';
-import { analyzeDocument, fileToBase64, checkServerHealth } from './api';
+import { analyzeDocument, fileToBase64, checkServerHealth, emptyPagesNotice } from './api';
 import { loadSettings } from './storage';
 import { getAgreementHistory, saveAgreementAnalysis, deleteAgreementAnalysis, clearAgreementHistory, type AgreementHistoryItem } from './historyDb';
 
@@ -202,7 +202,7 @@ function AgreementPage() {
           >
             <div className="upload-icon">{file ? '✅' : '📁'}</div>
             <h3>{file ? 'File Selected' : 'Drop your agreement here'}</h3>
-            <p>or click to browse (TXT, PDF, DOCX)</p>
+            <p>or click to browse (PDF, DOCX, ODT, RTF, HTML, Markdown, TXT, EML)</p>
             {file && (
               <div className="file-name">
                 📎 {file.name}
@@ -212,7 +212,7 @@ function AgreementPage() {
           <input
             ref={fileInputRef}
             type="file"
-            accept=".txt,.pdf,.docx"
+            accept=".txt,.pdf,.docx,.odt,.rtf,.html,.htm,.md,.markdown,.eml,.mbox"
             onChange={handleFileSelect}
             style={{ display: 'none' }}
           />
@@ -259,6 +259,9 @@ function AgreementPage() {
               {result.summary && (
                 <div className="summary">{result.summary}</div>
               )}
+              {emptyPagesNotice(result.document) && (
+                <div className="page-notice">⚠️ {emptyPagesNotice(result.document)}</div>
+              )}
             </div>
 
             {/* AI Insights Section */}
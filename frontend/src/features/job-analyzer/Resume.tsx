@@ -3,7 +3,7 @@ import type { DragEvent } from 'react';
 import { useNavigate } from 'react-router-dom';
 import './Resume.css';
 import type { ResumeAnalysisResult } from './types';
-import { analyzeResume, fileToBase64, checkServerHealth } from './api';
+import { analyzeResume, fileToBase64, checkServerHealth, emptyPagesNotice } from './api';
 import { loadSettings } from './storage';
 import { getResumeHistory, saveResumeAnalysis, deleteResumeAnalysis, clearResumeHistory, type ResumeHistoryItem } from './historyDb';
 
@@ -156,9 +156,9 @@ function ResumePage() {
                     >
                         <div className="upload-icon">{file ? '✅' : '📄'}</div>
                         <h3>{file ? file.name : 'Drop your resume here'}</h3>
-                        <p>TXT, PDF, or DOCX</p>
+                        <p>PDF, DOCX, ODT, RTF, HTML, Markdown or TXT</p>
                     </div>
-                    <input ref={fileInputRef} type="file" accept=".txt,.pdf,.docx"
+                    <input ref={fileInputRef} type="file" accept=".txt,.pdf,.docx,.odt,.rtf,.html,.htm,.md,.markdown"
                         onChange={handleFileSelect} style={{ display: 'none' }} />
                 </div>
 
@@ -179,6 +179,9 @@ function ResumePage() {
                             </div>
                             <div className="score-category">{result.score_category.replace('_', ' ')}</div>
                             <p className="summary">{result.summary}</p>
+                            {emptyPagesNotice(result.document) && (
+                                <p className="page-notice">⚠️ {emptyPagesNotice(result.document)}</p>
+                            )}
                         </div>
 
                         {/* Section Scores */}
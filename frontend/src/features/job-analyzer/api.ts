@@ -1,4 +1,4 @@
-import type { AnalyzeRequest, AnalysisResult, ApiError } from './types';
+import type { AnalyzeRequest, AnalysisResult, ApiError, DocumentInfo } from './types';
 
 const API_URL = import.meta.env.VITE_JOB_ANALYZER_API_URL || 'https://docs-backend-271230242037.asia-south1.run.app';
 
@@ -45,6 +45,17 @@ export function fileToBase64(file: File): Promise<string> {
     });
 }
 
+// Describes pages the server could not read, e.g. scanned images in a PDF
+export function emptyPagesNotice(document?: DocumentInfo): string | null {
+    const pages = document?.empty_pages;
+    if (!pages?.length) return null;
+    if (pages.length === 1) {
+        return `Page ${pages[0]} has no extractable text (it may be a scanned image) and was not analyzed.`;
+    }
+    const list = `${pages.slice(0, -1).join(', ')} and ${pages[pages.length - 1]}`;
+    return `Pages ${list} have no extractable text (they may be scanned images) and were not analyzed.`;
+}
+
 // Resume Analysis API
 export async function analyzeResume(request: { api_key: string; document: string; filename: string }): Promise<import('./types').ResumeAnalysisResult> {
     const response = await fetch(`${API_URL}/api/resume/analyze`, {
@@ -5,6 +5,13 @@ export interface Finding {
     quote: string;
 }
 
+export interface DocumentInfo {
+    filename: string;
+    format: string;
+    pages?: number;
+    empty_pages?: number[];
+}
+
 export interface AnalysisResult {
     risk_score: number;
     risk_level: 'LOW' | 'MEDIUM' | 'HIGH' | 'CRITICAL';
@@ -13,6 +20,7 @@ export interface AnalysisResult {
     risky_clauses: Finding[];
     missing_elements: string[];
     recommendations: string[];
+    document?: DocumentInfo;
 }
 
 export interface AnalyzeRequest {
@@ -66,4 +74,5 @@ export interface ResumeAnalysisResult {
     word_variety: ScoreSection;
     suggestions: ResumeSuggestion[];
     checklist: ChecklistItem[];
+    document?: DocumentInfo;
 }
@@ -4,15 +4,19 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
 // GeminiService handles all Gemini API interactions
 type GeminiService struct {
-	client *genai.Client
-	model  *genai.GenerativeModel
+	client    *genai.Client
+	model     *genai.GenerativeModel
+	modelName string
+	prices    map[string]Price
 }
 
 // Config holds configuration for Gemini service
@@ -54,6 +58,11 @@ func NewGeminiService(ctx context.Context, apiKey string) (*GeminiService, error
 	}
 	model := client.GenerativeModel(modelName)
 
+	prices, err := loadPrices()
+	if err != nil {
+		return nil, err
+	}
+
 	// Configure for long instructions handling
 	model.SetMaxOutputTokens(8192)
 	model.SetTemperature(0.7)
@@ -61,8 +70,10 @@ func NewGeminiService(ctx context.Context, apiKey string) (*GeminiService, error
 	model.SetTopK(40)
 
 	return &GeminiService{
-		client: client,
-		model:  model,
+		client:    client,
+		model:     model,
+		modelName: modelName,
+		prices:    prices,
 	}, nil
 }
 
@@ -72,7 +83,9 @@ func (g *GeminiService) Close() error {
 }
 
 // Chat sends a message with Anie's system instructions
-func (g *GeminiService) Chat(ctx context.Context, message string, additionalInstructions string) (string, error) {
+func (g *GeminiService) Chat(ctx context.Context, message string, additionalInstructions string) (string, *Usage, error) {
+	started := time.Now()
+
 	// Always use Anie's base instructions + any additional context
 	instructions := SystemInstructions
 	if additionalInstructions != "" {
@@ -84,14 +97,15 @@ func (g *GeminiService) Chat(ctx context.Context, message string, additionalInst
 
 	resp, err := g.model.GenerateContent(ctx, genai.Text(prompt))
 	if err != nil {
-		return "", fmt.Errorf("failed to generate content: %w", err)
+		return "", nil, fmt.Errorf("failed to generate content: %w", err)
 	}
 
-	return extractResponse(resp), nil
+	return extractResponse(resp), newUsage(g.modelName, resp.UsageMetadata, g.prices, started), nil
 }
 
 // ChatWithHistory maintains conversation context with Anie's persona
-func (g *GeminiService) ChatWithHistory(ctx context.Context, history []Message, additionalInstructions string) (string, error) {
+func (g *GeminiService) ChatWithHistory(ctx context.Context, history []Message, additionalInstructions string) (string, *Usage, error) {
+	started := time.Now()
 	chat := g.model.StartChat()
 
 	// Always use Anie's base instructions + any additional context
@@ -121,14 +135,18 @@ func (g *GeminiService) ChatWithHistory(ctx context.Context, history []Message,
 	lastMsg := history[len(history)-1]
 	resp, err := chat.SendMessage(ctx, genai.Text(lastMsg.Content))
 	if err != nil {
-		return "", fmt.Errorf("failed to send message: %w", err)
+		return "", nil, fmt.Errorf("failed to send message: %w", err)
 	}
 
-	return extractResponse(resp), nil
+	return extractResponse(resp), newUsage(g.modelName, resp.UsageMetadata, g.prices, started), nil
 }
 
-// StreamChat streams response for long outputs with Anie's persona
-func (g *GeminiService) StreamChat(ctx context.Context, message string, additionalInstructions string, onChunk func(string)) error {
+// StreamChat streams response for long outputs with Anie's persona and
+// returns the usage reported with the final chunk. A stream that fails
+// part-way returns an error along with the usage seen so far.
+func (g *GeminiService) StreamChat(ctx context.Context, message string, additionalInstructions string, onChunk func(string)) (*Usage, error) {
+	started := time.Now()
+
 	// Always use Anie's base instructions + any additional context
 	instructions := SystemInstructions
 	if additionalInstructions != "" {
@@ -139,11 +157,20 @@ func (g *GeminiService) StreamChat(ctx context.Context, message string, addition
 
 	iter := g.model.GenerateContentStream(ctx, genai.Text(prompt))
 
+	// Usage metadata is cumulative; the last chunk carries the final counts
+	var meta *genai.UsageMetadata
 	for {
 		resp, err := iter.Next()
-		if err != nil {
+		if err == iterator.Done {
 			break
 		}
+		if err != nil {
+			// Report what was streamed so far along with the error
+			return newUsage(g.modelName, meta, g.prices, started), fmt.Errorf("stream failed: %w", err)
+		}
+		if resp.UsageMetadata != nil {
+			meta = resp.UsageMetadata
+		}
 
 		text := extractResponse(resp)
 		if text != "" {
@@ -151,7 +178,7 @@ func (g *GeminiService) StreamChat(ctx context.Context, message string, addition
 		}
 	}
 
-	return nil
+	return newUsage(g.modelName, meta, g.prices, started), nil
 }
 
 // Message represents a chat message
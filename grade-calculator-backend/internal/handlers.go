@@ -17,22 +17,25 @@ type ChatRequestBody struct {
 
 type ChatResponseBody struct {
 	Response string `json:"response"`
+	Usage    *Usage `json:"usage,omitempty"`
 	Error    string `json:"error,omitempty"`
 }
 
 type StreamChunk struct {
 	Content string `json:"content"`
 	Done    bool   `json:"done"`
+	Usage   *Usage `json:"usage,omitempty"` // Set on the final chunk
 }
 
 // Handlers struct holds dependencies
 type Handlers struct {
-	gemini *GeminiService
+	gemini  *GeminiService
+	metrics *Metrics
 }
 
 // NewHandlers creates handlers with Gemini service
 func NewHandlers(gemini *GeminiService) *Handlers {
-	return &Handlers{gemini: gemini}
+	return &Handlers{gemini: gemini, metrics: NewMetrics()}
 }
 
 // HandleChat handles chat requests
@@ -56,23 +59,26 @@ func (h *Handlers) HandleChat(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
 	var response string
+	var usage *Usage
 	var err error
 
 	if len(req.History) > 0 {
 		// Use chat with history for conversation context
-		response, err = h.gemini.ChatWithHistory(ctx, req.History, req.Instructions)
+		response, usage, err = h.gemini.ChatWithHistory(ctx, req.History, req.Instructions)
 	} else {
 		// Simple single message chat
-		response, err = h.gemini.Chat(ctx, req.Message, req.Instructions)
+		response, usage, err = h.gemini.Chat(ctx, req.Message, req.Instructions)
 	}
 
 	if err != nil {
 		log.Printf("Gemini error: %v", err)
+		h.metrics.RecordError("chat")
 		writeError(w, "Failed to generate response", http.StatusInternalServerError)
 		return
 	}
+	h.metrics.Record("chat", usage)
 
-	writeJSON(w, ChatResponseBody{Response: response})
+	writeJSON(w, ChatResponseBody{Response: response, Usage: usage})
 }
 
 // HandleStreamChat handles streaming chat requests
@@ -107,7 +113,7 @@ func (h *Handlers) HandleStreamChat(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
-	err := h.gemini.StreamChat(ctx, req.Message, req.Instructions, func(chunk string) {
+	usage, err := h.gemini.StreamChat(ctx, req.Message, req.Instructions, func(chunk string) {
 		data, _ := json.Marshal(StreamChunk{Content: chunk, Done: false})
 		fmt.Fprintf(w, "data: %s\n\n", data)
 		flusher.Flush()
@@ -115,10 +121,13 @@ func (h *Handlers) HandleStreamChat(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		log.Printf("Stream error: %v", err)
+		h.metrics.RecordError("stream")
+	} else {
+		h.metrics.Record("stream", usage)
 	}
 
 	// Send done signal
-	data, _ := json.Marshal(StreamChunk{Content: "", Done: true})
+	data, _ := json.Marshal(StreamChunk{Content: "", Done: true, Usage: usage})
 	fmt.Fprintf(w, "data: %s\n\n", data)
 	flusher.Flush()
 }
@@ -128,6 +137,11 @@ func (h *Handlers) HandleHealth(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, map[string]string{"status": "ok", "model": "gemini-2.0-flash"})
 }
 
+// HandleMetrics returns chat usage and cost totals since the server started
+func (h *Handlers) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.metrics.Snapshot())
+}
+
 // Helper functions
 func writeJSON(w http.ResponseWriter, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// Usage reports the tokens, latency and estimated cost of a chat response
+type Usage struct {
+	Model            string  `json:"model"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	LatencyMS        int64   `json:"latency_ms"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+// Price is a model's list price in USD per million tokens
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// DefaultPrices returns list prices for common Gemini models. Keys match
+// model names by prefix.
+func DefaultPrices() map[string]Price {
+	return map[string]Price{
+		"gemini-2.0-flash":      {Input: 0.10, Output: 0.40},
+		"gemini-2.0-flash-lite": {Input: 0.075, Output: 0.30},
+		"gemini-2.5-flash":      {Input: 0.30, Output: 2.50},
+		"gemini-2.5-flash-lite": {Input: 0.10, Output: 0.40},
+		"gemini-2.5-pro":        {Input: 1.25, Output: 10.00},
+	}
+}
+
+// loadPrices returns the default prices, overridden by the GEMINI_PRICES
+// environment variable (JSON, e.g. {"gemini-2.0-flash": {"input": 0.1, "output": 0.4}})
+func loadPrices() (map[string]Price, error) {
+	prices := DefaultPrices()
+	if value := os.Getenv("GEMINI_PRICES"); value != "" {
+		var overrides map[string]Price
+		if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+			return nil, fmt.Errorf("invalid GEMINI_PRICES: %w", err)
+		}
+		for model, price := range overrides {
+			prices[model] = price
+		}
+	}
+	return prices, nil
+}
+
+// newUsage builds the usage of a response from its metadata
+func newUsage(model string, meta *genai.UsageMetadata, prices map[string]Price, started time.Time) *Usage {
+	usage := &Usage{Model: model, LatencyMS: time.Since(started).Milliseconds()}
+	if meta == nil {
+		return usage
+	}
+	usage.PromptTokens = int(meta.PromptTokenCount)
+	usage.CompletionTokens = int(meta.CandidatesTokenCount)
+	usage.TotalTokens = int(meta.TotalTokenCount)
+
+	// Longest matching prefix wins; unknown models cost 0
+	var price Price
+	longest := -1
+	for name, p := range prices {
+		if strings.HasPrefix(model, name) && len(name) > longest {
+			price, longest = p, len(name)
+		}
+	}
+	cost := (float64(usage.PromptTokens)*price.Input + float64(usage.CompletionTokens)*price.Output) / 1e6
+	usage.EstimatedCostUSD = math.Round(cost*1e6) / 1e6
+	return usage
+}
+
+// MetricsTotals aggregates the usage of a set of chat requests
+type MetricsTotals struct {
+	Requests         int     `json:"requests"`
+	Errors           int     `json:"errors"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+	AvgLatencyMS     int64   `json:"avg_latency_ms"`
+
+	latencyMS int64
+}
+
+// Metrics aggregates chat usage in memory since process start
+type Metrics struct {
+	mu     sync.Mutex
+	since  time.Time
+	byKind map[string]*MetricsTotals
+}
+
+// NewMetrics creates an empty Metrics
+func NewMetrics() *Metrics {
+	return &Metrics{since: time.Now().UTC(), byKind: map[string]*MetricsTotals{}}
+}
+
+// Record adds a successful request of the given kind ("chat" or "stream")
+func (m *Metrics) Record(kind string, usage *Usage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range []*MetricsTotals{m.totals("total"), m.totals(kind)} {
+		t.Requests++
+		t.PromptTokens += usage.PromptTokens
+		t.CompletionTokens += usage.CompletionTokens
+		t.TotalTokens += usage.TotalTokens
+		t.EstimatedCostUSD = math.Round((t.EstimatedCostUSD+usage.EstimatedCostUSD)*1e6) / 1e6
+		t.latencyMS += usage.LatencyMS
+		t.AvgLatencyMS = t.latencyMS / int64(t.Requests)
+	}
+}
+
+// RecordError adds a failed request of the given kind
+func (m *Metrics) RecordError(kind string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.totals("total").Errors++
+	m.totals(kind).Errors++
+}
+
+// MetricsSnapshot is a point-in-time copy of the recorded metrics
+type MetricsSnapshot struct {
+	Since  time.Time                `json:"since"`
+	Totals map[string]MetricsTotals `json:"totals"` // By kind, plus "total" for all requests
+}
+
+// Snapshot returns a copy of the current metrics
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snap := MetricsSnapshot{Since: m.since, Totals: make(map[string]MetricsTotals, len(m.byKind))}
+	for kind, t := range m.byKind {
+		snap.Totals[kind] = *t
+	}
+	return snap
+}
+
+func (m *Metrics) totals(kind string) *MetricsTotals {
+	t, ok := m.byKind[kind]
+	if !ok {
+		t = &MetricsTotals{}
+		m.byKind[kind] = t
+	}
+	return t
+}
@@ -32,6 +32,7 @@ func main() {
 	http.HandleFunc("/api/chat", enableCORS(handlers.HandleChat))
 	http.HandleFunc("/api/chat/stream", enableCORS(handlers.HandleStreamChat))
 	http.HandleFunc("/api/health", enableCORS(handlers.HandleHealth))
+	http.HandleFunc("/api/metrics", enableCORS(handlers.HandleMetrics))
 
 	port := os.Getenv("PORT")
 	if port == "" {